@@ -4,6 +4,7 @@ import (
 	"context"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -17,6 +18,13 @@ type Adapter interface {
 	Close() error
 }
 
+// Addresser is implemented by adapters which know the user the bot is
+// connected as and how a user is mentioned in a message text.
+type Addresser interface {
+	SelfID() string
+	Mention(userID string) string
+}
+
 // Bot struct
 type Bot struct {
 	Context context.Context
@@ -26,7 +34,12 @@ type Bot struct {
 	Brain   *Brain
 	Logger  *zap.Logger
 
-	initErr error
+	initErr         error
+	strict          bool
+	threadedReplies bool
+
+	rejectedMu sync.Mutex
+	rejected   RegistrationErrors
 }
 
 // NewBot generates new bot
@@ -63,6 +76,14 @@ func (b *Bot) Run() error {
 		return errors.Wrap(b.initErr, "failed to init bot")
 	}
 
+	if rejected := b.RejectedRegistrations(); len(rejected) > 0 {
+		if b.strict {
+			return errors.Wrap(rejected[0], "failed to register handlers")
+		}
+
+		return errors.Wrap(rejected, "failed to register handlers")
+	}
+
 	b.Adapter.Register(b.Brain)
 	b.Brain.Emit(InitEvent{})
 
@@ -79,41 +100,233 @@ func (b *Bot) Run() error {
 	return nil
 }
 
-// Respond gets the message and register the handlers
-func (b *Bot) Respond(msg string, fun func(Message) error) {
+// Use adds middleware which is applied to every handler of the bot.
+func (b *Bot) Use(mws ...Middleware) {
+	b.Brain.Use(mws...)
+}
+
+// RejectedRegistrations returns every handler registration which failed.
+// Run refuses to start the bot as long as there are rejected registrations.
+func (b *Bot) RejectedRegistrations() RegistrationErrors {
+	b.rejectedMu.Lock()
+	defer b.rejectedMu.Unlock()
+
+	rejected := make(RegistrationErrors, len(b.rejected))
+	copy(rejected, b.rejected)
+	return rejected
+}
+
+// Reject records a failed registration, e.g. of a registration helper of an
+// adapter, so Run refuses to start.
+func (b *Bot) Reject(err *RegistrationError) {
+	b.Logger.Error("Failed to register a handler", zap.Error(err))
+
+	b.rejectedMu.Lock()
+	b.rejected = append(b.rejected, err)
+	b.rejectedMu.Unlock()
+}
+
+// strictErr returns the first rejected registration in strict mode, further
+// handlers are not registered then.
+func (b *Bot) strictErr() error {
+	if !b.strict {
+		return nil
+	}
+
+	b.rejectedMu.Lock()
+	defer b.rejectedMu.Unlock()
+
+	if len(b.rejected) == 0 {
+		return nil
+	}
+
+	return b.rejected[0]
+}
+
+// RegisterHandler registers a raw event handler on the bot's brain.
+// A rejected handler is recorded so Run refuses to start.
+func (b *Bot) RegisterHandler(fun interface{}, mws ...Middleware) (*Handle, error) {
+	if err := b.strictErr(); err != nil {
+		return nil, err
+	}
+
+	handle, err := b.Brain.RegisterHandler(fun, mws...)
+	if err != nil {
+		b.Reject(err.(*RegistrationError))
+		return nil, err
+	}
+
+	return handle, nil
+}
+
+// RegisterMatcher registers a handler which decides itself which events it
+// handles on the bot's brain, see Brain.RegisterMatcher. A rejected handler
+// is recorded so Run refuses to start.
+func (b *Bot) RegisterMatcher(fun interface{}) (*Handle, error) {
+	if err := b.strictErr(); err != nil {
+		return nil, err
+	}
+
+	handle, err := b.Brain.RegisterMatcher(fun)
+	if err != nil {
+		b.Reject(err.(*RegistrationError))
+		return nil, err
+	}
+
+	return handle, nil
+}
+
+// Respond gets the message and register the handlers. Only messages which
+// are addressed to the bot and not sent by the bot itself are considered,
+// the address is stripped before the expression is matched. The given
+// middleware runs only for messages matching the expression. The returned
+// Handle can be used to remove the handler again. An invalid expression is
+// recorded so Run refuses to start.
+func (b *Bot) Respond(msg string, fun func(Message) error, mws ...Middleware) (*Handle, error) {
 	expr := "^" + msg + "$"
-	if expr == "" {
-		return
+	if !strings.HasPrefix(expr, "^(?i)") {
+		expr = "^(?i)" + expr[1:]
 	}
 
-	if expr[0] == '^' {
-		if !strings.HasPrefix(expr, "^(?i)") {
-			expr = "^(?i)" + expr[1:]
+	return b.registerMessageHandler("respond: "+msg, expr, fun, mws, b.addressedText)
+}
+
+// addressedText returns the text of the message without the address, if the
+// message is addressed to the bot. A message is addressed to the bot if it
+// starts with a mention of the bot or with its name, or if it was sent as
+// a direct message.
+func (b *Bot) addressedText(evt ReciveMessageEvent) (string, bool) {
+	if evt.FromSelf {
+		return "", false
+	}
+
+	text := strings.TrimSpace(evt.Text)
+
+	if addresser, ok := b.Adapter.(Addresser); ok {
+		if selfID := addresser.SelfID(); selfID != "" {
+			if rest, ok := trimAddress(text, addresser.Mention(selfID)); ok {
+				return rest, true
+			}
 		}
-	} else {
-		if !strings.HasPrefix(expr, "(?i)") {
-			expr = "(?i)" + expr
+	}
+
+	if b.Name != "" {
+		for _, name := range []string{b.Name, "@" + b.Name} {
+			if rest, ok := trimAddress(text, name); ok {
+				return rest, true
+			}
 		}
 	}
 
+	if evt.Direct {
+		return text, true
+	}
+
+	return "", false
+}
+
+const addressSeparators = ":, \t\r\n"
+
+// trimAddress removes the address prefix from the text. The prefix is only
+// matched as a whole word.
+func trimAddress(text, address string) (string, bool) {
+	if address == "" || len(text) < len(address) || !strings.EqualFold(text[:len(address)], address) {
+		return "", false
+	}
+
+	rest := text[len(address):]
+	if rest != "" && !strings.ContainsRune(addressSeparators, rune(rest[0])) {
+		return "", false
+	}
+
+	return strings.TrimLeft(rest, addressSeparators), true
+}
+
+// HearConfig controls how Hear handlers match messages.
+type HearConfig struct {
+	// CaseSensitive disables the default case insensitive matching.
+	CaseSensitive bool
+	// IncludeSelf makes the handler also match messages sent by the bot itself.
+	// Some adapters only pass them on when asked to, e.g. the slack adapter
+	// with slack.WithOwnMessages.
+	IncludeSelf bool
+}
+
+// Hear registers a handler for every message which contains a match of the
+// expression anywhere in its text. Matching is case insensitive and messages
+// sent by the bot itself are ignored.
+func (b *Bot) Hear(expr string, fun func(Message) error, mws ...Middleware) (*Handle, error) {
+	return b.HearWith(expr, HearConfig{}, fun, mws...)
+}
+
+// HearWith is like Hear but matches messages according to the given config.
+func (b *Bot) HearWith(expr string, conf HearConfig, fun func(Message) error, mws ...Middleware) (*Handle, error) {
+	name := "hear: " + expr
+	if !conf.CaseSensitive && !strings.HasPrefix(expr, "(?i)") {
+		expr = "(?i)" + expr
+	}
+
+	return b.registerMessageHandler(name, expr, fun, mws, func(evt ReciveMessageEvent) (string, bool) {
+		return evt.Text, conf.IncludeSelf || !evt.FromSelf
+	})
+}
+
+// registerMessageHandler registers fun for messages accepted by accept whose
+// text matches the expression.
+func (b *Bot) registerMessageHandler(
+	name, expr string,
+	fun func(Message) error,
+	mws []Middleware,
+	accept func(ReciveMessageEvent) (string, bool),
+) (*Handle, error) {
+	if err := b.strictErr(); err != nil {
+		return nil, err
+	}
+
+	if fun == nil {
+		err := &RegistrationError{Handler: name, Err: errors.New("message handler is nil")}
+		b.Reject(err)
+		return nil, err
+	}
+
 	regex, err := regexp.Compile(expr)
 	if err != nil {
-		b.Logger.Error("Failed to add Response handler", zap.Error(err))
-		return
+		regErr := &RegistrationError{Handler: name, Err: err}
+		b.Reject(regErr)
+		return nil, regErr
 	}
 
-	b.Brain.RegisterHandler(func(ctx context.Context, evt ReciveMessageEvent) error {
-		matches := regex.FindStringSubmatch(evt.Text)
+	return b.Brain.registerHandler(func(ctx context.Context, evt ReciveMessageEvent) error {
+		text, ok := accept(evt)
+		if !ok {
+			return nil
+		}
+
+		matches := regex.FindStringSubmatch(text)
 		if len(matches) == 0 {
 			return nil
 		}
 
-		return fun(Message{
-			Context:  ctx,
-			Text:     evt.Text,
-			ChannelD: evt.ChannelD,
-			Matches:  matches[1:],
-			adapter:  b.Adapter,
-		})
-	})
+		handler := func(ctx context.Context, _ interface{}) error {
+			return fun(Message{
+				Context:  ctx,
+				Text:     evt.Text,
+				ChannelD: evt.ChannelD,
+				Matches:  matches[1:],
+				UserID:   evt.UserID,
+				SentAt:   evt.SentAt,
+				ID:       evt.ID,
+				ThreadID: evt.ThreadID,
+				FromSelf: evt.FromSelf,
+				FromBot:  evt.FromBot,
+				Subtype:  evt.Subtype,
+				Direct:   evt.Direct,
+				Raw:      evt.Raw,
+				adapter:  b.Adapter,
+				threaded: b.threadedReplies,
+			})
+		}
+
+		return b.Brain.Chain(handler, mws...)(ctx, evt)
+	}, name, nil, true)
 }
@@ -0,0 +1,213 @@
+package zha
+
+import (
+	"context"
+	"errors"
+	"regexp/syntax"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type nopAdapter struct{}
+
+func (nopAdapter) Register(*Brain)            {}
+func (nopAdapter) Send(text, ch string) error { return nil }
+func (nopAdapter) Close() error               { return nil }
+
+type addressAdapter struct {
+	nopAdapter
+}
+
+func (addressAdapter) SelfID() string           { return "U0BOT" }
+func (addressAdapter) Mention(id string) string { return "<@" + id + ">" }
+
+func newTestBot(opts ...Option) *Bot {
+	b := NewBot("zha", opts...)
+	b.Adapter = nopAdapter{}
+	return b
+}
+
+func TestRegisterHandlerErrors(t *testing.T) {
+	brain := newTestBot().Brain
+
+	invalid := []interface{}{
+		nil,
+		"not a function",
+		func() {},
+		func(a, b, c ReciveMessageEvent) {},
+		func(string) {},
+		func(int, ReciveMessageEvent) {},
+		func(ReciveMessageEvent) int { return 0 },
+		func(ReciveMessageEvent) (error, error) { return nil, nil },
+	}
+
+	for _, fun := range invalid {
+		handle, err := brain.RegisterHandler(fun)
+		if handle != nil {
+			t.Errorf("expected no handle for %#v", fun)
+		}
+		if _, ok := err.(*RegistrationError); !ok {
+			t.Errorf("expected registration error for %#v, got %#v", fun, err)
+		}
+	}
+
+	if len(brain.Handlers()) != 0 {
+		t.Errorf("invalid handlers were registered %#v", brain.Handlers())
+	}
+}
+
+func TestRunRefusesRejectedRegistrations(t *testing.T) {
+	bot := newTestBot()
+
+	var syntaxErr *syntax.Error
+	if _, err := bot.Respond("broken (", func(Message) error { return nil }); !errors.As(err, &syntaxErr) {
+		t.Errorf("expected syntax error for invalid expression, got %#v", err)
+	}
+	if _, err := bot.RegisterHandler(func() {}); err == nil {
+		t.Error("expected error for invalid handler")
+	}
+	if _, err := bot.Respond("valid", func(Message) error { return nil }); err != nil {
+		t.Errorf("unexpected error %#v", err)
+	}
+
+	rejected := bot.RejectedRegistrations()
+	if len(rejected) != 2 {
+		t.Fatalf("expected two rejected registrations, got %#v", rejected)
+	}
+	if rejected[0].Handler != "respond: broken (" {
+		t.Errorf("unexpected rejected handler %q", rejected[0].Handler)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bot.Context = ctx
+	if err := bot.Run(); err == nil {
+		t.Error("bot should not start with rejected registrations")
+	}
+}
+
+func TestConcurrentRegistrations(t *testing.T) {
+	bot := newTestBot()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bot.RegisterHandler(func() {})
+			bot.RejectedRegistrations()
+		}()
+	}
+	wg.Wait()
+
+	if rejected := bot.RejectedRegistrations(); len(rejected) != 10 {
+		t.Errorf("expected ten rejected registrations, got %d", len(rejected))
+	}
+}
+
+func TestStrictRegistration(t *testing.T) {
+	bot := newTestBot(WithStrictRegistration())
+
+	_, first := bot.Respond("broken (", func(Message) error { return nil })
+	if _, ok := first.(*RegistrationError); !ok {
+		t.Fatalf("expected a registration error, got %#v", first)
+	}
+
+	if _, err := bot.Respond("valid", func(Message) error { return nil }); err != first {
+		t.Errorf("expected later registrations to fail with the first error, got %#v", err)
+	}
+	if len(bot.Brain.Handlers()) != 0 {
+		t.Errorf("unexpected handlers %v", bot.Brain.Handlers())
+	}
+
+	if err := bot.Run(); err == nil || !strings.HasSuffix(err.Error(), first.Error()) {
+		t.Errorf("expected Run to fail with the first error, got %#v", err)
+	}
+}
+
+func TestHear(t *testing.T) {
+	bot := newTestBot()
+
+	var matched []string
+	record := func(msg Message) error {
+		matched = append(matched, msg.Matches[0])
+		return nil
+	}
+
+	bot.Hear(`(TICKET-\d+)`, record)
+	bot.HearWith(`(deploy failed)`, HearConfig{CaseSensitive: true, IncludeSelf: true}, record)
+
+	for _, evt := range []ReciveMessageEvent{
+		{Text: "please look at ticket-42 today"},
+		{Text: "TICKET-7", FromSelf: true},
+		{Text: "the deploy failed again", FromSelf: true},
+		{Text: "Deploy Failed"},
+	} {
+		bot.Brain.handle(context.Background(), event{Data: evt})
+	}
+
+	expected := []string{"ticket-42", "deploy failed"}
+	if len(matched) != len(expected) {
+		t.Fatalf("expected matches %v, got %v", expected, matched)
+	}
+	for i := range expected {
+		if matched[i] != expected[i] {
+			t.Errorf("expected matches %v, got %v", expected, matched)
+			break
+		}
+	}
+}
+
+func TestRespondIsAnchored(t *testing.T) {
+	bot := newTestBot()
+
+	calls := 0
+	bot.Respond("ping", func(Message) error {
+		calls++
+		return nil
+	})
+
+	bot.Brain.handle(context.Background(), event{Data: ReciveMessageEvent{Text: "zha PING"}})
+	bot.Brain.handle(context.Background(), event{Data: ReciveMessageEvent{Text: "zha did you ping me"}})
+
+	if calls != 1 {
+		t.Errorf("expected one call, got %d", calls)
+	}
+}
+
+func TestRespondOnlyWhenAddressed(t *testing.T) {
+	bot := newTestBot()
+	bot.Adapter = addressAdapter{}
+
+	var commands []string
+	bot.Respond("deploy (.+)", func(msg Message) error {
+		commands = append(commands, msg.Matches[0])
+		return nil
+	})
+
+	for _, evt := range []ReciveMessageEvent{
+		{Text: "deploy ignored"},
+		{Text: "zhadeploy ignored"},
+		{Text: "<@U0OTHER> deploy ignored"},
+		{Text: "zha deploy name"},
+		{Text: "ZHA: deploy colon"},
+		{Text: "@zha, deploy at"},
+		{Text: "<@U0BOT> deploy mention"},
+		{Text: "deploy direct", Direct: true},
+		{Text: "zha deploy self", FromSelf: true},
+	} {
+		bot.Brain.handle(context.Background(), event{Data: evt})
+	}
+
+	expected := []string{"name", "colon", "at", "mention", "direct"}
+	if len(commands) != len(expected) {
+		t.Fatalf("expected commands %v, got %v", expected, commands)
+	}
+	for i := range expected {
+		if commands[i] != expected[i] {
+			t.Errorf("expected commands %v, got %v", expected, commands)
+			break
+		}
+	}
+}
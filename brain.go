@@ -2,21 +2,34 @@ package zha
 
 import (
 	"context"
-	"fmt"
+	"hash/fnv"
 	"reflect"
+	"runtime"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
-	"go.uber.org/zap/zapcore"
 )
 
 // Brain is a event processor
 type Brain struct {
-	input          chan event
+	input          *eventQueue
 	logger         *zap.Logger
 	handlerTimeout time.Duration
-	handlers       map[reflect.Type][]eventHandler
+	concurrency    int
+
+	mu         sync.RWMutex
+	handlers   map[reflect.Type][]registeredHandler
+	middleware []Middleware
+	lastID     uint64
+}
+
+type registeredHandler struct {
+	HandlerInfo
+	fun HandlerFunc
+	// matcher handlers apply the middleware themselves, once an event matched.
+	matcher bool
 }
 
 type event struct {
@@ -24,47 +37,192 @@ type event struct {
 	callbacks []func(event)
 }
 
-type eventHandler func(context.Context, reflect.Value) error
+// eventQueue is an unbounded FIFO queue of events, so neither Emit nor the
+// dispatcher ever block on a slow consumer.
+type eventQueue struct {
+	mu     sync.Mutex
+	events []event
+	ready  chan struct{}
+}
+
+func newEventQueue() *eventQueue {
+	return &eventQueue{ready: make(chan struct{}, 1)}
+}
+
+// push appends the event and signals the consumer.
+func (q *eventQueue) push(evt event) {
+	q.mu.Lock()
+	q.events = append(q.events, evt)
+	q.mu.Unlock()
+
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// drain removes and returns the queued events in the order they were pushed.
+func (q *eventQueue) drain() []event {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	events := q.events
+	q.events = nil
+	return events
+}
 
 // NewBrain returns new Brain
 func NewBrain(logger *zap.Logger, timeout time.Duration) *Brain {
 	return &Brain{
 		logger:         logger.Named("brain"),
-		input:          make(chan event, 10),
-		handlers:       make(map[reflect.Type][]eventHandler),
+		input:          newEventQueue(),
+		handlers:       make(map[reflect.Type][]registeredHandler),
 		handlerTimeout: timeout,
+		concurrency:    1,
 	}
 }
 
-// RegisterHandler is a register of a handler functions
-func (b *Brain) RegisterHandler(fun interface{}) {
-	logErr := func(err error, fields ...zapcore.Field) {
-		b.logger.Error("Failed to register a handler: "+err.Error(), fields...)
-	}
+// Use adds middleware which is applied to every registered handler. Matcher
+// handlers, e.g. of Respond, Hear and Command, only apply it to the events
+// they match.
+func (b *Brain) Use(mws ...Middleware) {
+	b.mu.Lock()
+	b.middleware = append(b.middleware, mws...)
+	b.mu.Unlock()
+}
+
+// RegisterHandler is a register of a handler functions. The given middleware
+// is applied only to this handler, inside of the middleware added with Use.
+// The returned Handle can be used to unregister the handler again. Handlers
+// with an invalid signature are rejected with a *RegistrationError.
+func (b *Brain) RegisterHandler(fun interface{}, mws ...Middleware) (*Handle, error) {
+	return b.registerHandler(fun, "", mws, false)
+}
+
+// RegisterMatcher registers a handler which decides itself which events it
+// handles, e.g. by matching a pattern. The middleware added with Use is not
+// applied to the handler, it wraps the matched events with Chain instead, so
+// the middleware runs only for the events the handler actually handles.
+func (b *Brain) RegisterMatcher(fun interface{}) (*Handle, error) {
+	return b.registerHandler(fun, "", nil, true)
+}
+
+// Chain wraps the handler with the middleware added with Use and then with
+// the given middleware.
+func (b *Brain) Chain(handler HandlerFunc, mws ...Middleware) HandlerFunc {
+	b.mu.RLock()
+	all := make([]Middleware, 0, len(b.middleware)+len(mws))
+	all = append(all, b.middleware...)
+	b.mu.RUnlock()
+
+	return chainMiddleware(handler, append(all, mws...))
+}
 
+func (b *Brain) registerHandler(fun interface{}, name string, mws []Middleware, matcher bool) (*Handle, error) {
 	handler := reflect.ValueOf(fun)
-	handlerType := handler.Type()
+	if name == "" {
+		name = handlerName(handler)
+	}
 
 	if handler.Kind() != reflect.Func {
-		logErr(errors.New("event handler is not a function"))
-		return
+		return nil, &RegistrationError{Handler: name, Err: errors.New("event handler is not a function")}
 	}
 
+	if handler.IsNil() {
+		return nil, &RegistrationError{Handler: name, Err: errors.New("event handler is nil")}
+	}
+
+	handlerType := handler.Type()
+
 	evtType, withContext, err := b.checkHandlerParams(handlerType)
 	if err != nil {
-		logErr(err)
-		return
+		return nil, &RegistrationError{Handler: name, Err: err}
 	}
 
 	returnsErr, err := b.checkHandlerReturnValues(handlerType)
+	if err != nil {
+		return nil, &RegistrationError{Handler: name, Err: err}
+	}
 
 	b.logger.Debug(
 		"Registering new event handler",
 		zap.String("event_type", evtType.Name()),
 	)
 
-	handlerFun := b.newHandlerFunc(handler, withContext, returnsErr)
-	b.handlers[evtType] = append(b.handlers[evtType], handlerFun)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	registered := registeredHandler{
+		HandlerInfo: HandlerInfo{ID: b.lastID, EventType: evtType, Name: name},
+		fun:         chainMiddleware(b.newHandlerFunc(handler, withContext, returnsErr), mws),
+		matcher:     matcher,
+	}
+	b.handlers[evtType] = append(b.handlers[evtType], registered)
+
+	return &Handle{brain: b, info: registered.HandlerInfo}, nil
+}
+
+func handlerName(handler reflect.Value) string {
+	if !handler.IsValid() {
+		return "<nil>"
+	}
+
+	if handler.Kind() != reflect.Func || handler.IsNil() {
+		return handler.Type().String()
+	}
+
+	return runtime.FuncForPC(handler.Pointer()).Name()
+}
+
+// unregister removes the handler with the given id. The handlers slice is
+// copied so events which are already being handled are not affected.
+func (b *Brain) unregister(evtType reflect.Type, id uint64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	handlers := b.handlers[evtType]
+	for i, handler := range handlers {
+		if handler.ID != id {
+			continue
+		}
+
+		remaining := make([]registeredHandler, 0, len(handlers)-1)
+		remaining = append(remaining, handlers[:i]...)
+		remaining = append(remaining, handlers[i+1:]...)
+		if len(remaining) == 0 {
+			delete(b.handlers, evtType)
+		} else {
+			b.handlers[evtType] = remaining
+		}
+
+		b.logger.Debug(
+			"Unregistered event handler",
+			zap.String("event_type", evtType.Name()),
+			zap.String("handler", handler.Name),
+		)
+
+		return true
+	}
+
+	return false
+}
+
+// Handlers lists the registered handlers per event type.
+func (b *Brain) Handlers() map[reflect.Type][]HandlerInfo {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	handlers := make(map[reflect.Type][]HandlerInfo, len(b.handlers))
+	for typ, registered := range b.handlers {
+		infos := make([]HandlerInfo, 0, len(registered))
+		for _, handler := range registered {
+			infos = append(infos, handler.HandlerInfo)
+		}
+		handlers[typ] = infos
+	}
+
+	return handlers
 }
 
 func (b *Brain) checkHandlerReturnValues(handlerFun reflect.Type) (returnsErr bool, err error) {
@@ -110,23 +268,16 @@ func (b *Brain) checkHandlerParams(handlerFun reflect.Type) (evtType reflect.Typ
 	return evtType, withContext, nil
 }
 
-func (b *Brain) newHandlerFunc(handler reflect.Value, withContext, returnsErr bool) eventHandler {
-	return func(ctx context.Context, evt reflect.Value) (handlerErr error) {
-		defer func() {
-			if err := recover(); err != nil {
-				fmt.Println(err)
-				handlerErr = errors.Errorf("handler panic: %#v", err)
-			}
-		}()
-
+func (b *Brain) newHandlerFunc(handler reflect.Value, withContext, returnsErr bool) HandlerFunc {
+	return func(ctx context.Context, evt interface{}) error {
 		var args []reflect.Value
 		if withContext {
 			args = []reflect.Value{
 				reflect.ValueOf(ctx),
-				evt,
+				reflect.ValueOf(evt),
 			}
 		} else {
-			args = []reflect.Value{evt}
+			args = []reflect.Value{reflect.ValueOf(evt)}
 		}
 
 		results := handler.Call(args)
@@ -138,33 +289,90 @@ func (b *Brain) newHandlerFunc(handler reflect.Value, withContext, returnsErr bo
 	}
 }
 
-// Process is processing the incoming events and process the outgoing event
+// Process is processing the incoming events and process the outgoing event.
+// Events are distributed over the configured number of workers. Events which
+// implement ChannelEvent are always handled by the same worker, so the order
+// of events within a channel is preserved. Every worker has its own queue,
+// so a slow channel does not hold up the others.
 func (b *Brain) Process(ctx context.Context) {
+	var wg sync.WaitGroup
+	workers := make([]*eventQueue, b.concurrency)
+	for i := range workers {
+		workers[i] = newEventQueue()
+
+		wg.Add(1)
+		go func(queue *eventQueue) {
+			defer wg.Done()
+			for {
+				select {
+				case <-queue.ready:
+					for _, evt := range queue.drain() {
+						// events which did not start before shutdown are dropped
+						if ctx.Err() != nil {
+							break
+						}
+
+						b.handle(ctx, evt)
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(workers[i])
+	}
+
+	next := 0
 	for {
 		select {
-		case evt := <-b.input:
-			b.handle(ctx, evt)
+		case <-b.input.ready:
+			for _, evt := range b.input.drain() {
+				var worker int
+				if channelEvt, ok := evt.Data.(ChannelEvent); ok {
+					worker = workerIndex(channelEvt.GetRoomID(), len(workers))
+				} else {
+					worker = next % len(workers)
+					next++
+				}
+
+				workers[worker].push(evt)
+			}
 		case <-ctx.Done():
-			b.handle(ctx, event{Data: ShutdownEvent{}})
+			wg.Wait()
+			b.handle(context.Background(), event{Data: ShutdownEvent{}})
 			return
 		}
 	}
 }
 
+func workerIndex(key string, workers int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(workers))
+}
+
 func (b *Brain) handle(ctx context.Context, evt event) {
-	event := reflect.ValueOf(evt.Data)
-	typ := event.Type()
+	typ := reflect.TypeOf(evt.Data)
+
+	b.mu.RLock()
+	handlers := b.handlers[typ]
+	middleware := b.middleware
+	b.mu.RUnlock()
 
 	b.logger.Debug(
 		"Handling new event",
 		zap.String("event_type", typ.Name()),
-		zap.Int("handlers", len(b.handlers[typ])),
+		zap.Int("handlers", len(handlers)),
 	)
 
-	for _, handler := range b.handlers[typ] {
-		err := b.executeHandler(ctx, handler, event)
+	for _, handler := range handlers {
+		fun := handler.fun
+		if !handler.matcher {
+			fun = chainMiddleware(fun, middleware)
+		}
+
+		err := b.executeHandler(ctx, fun, evt.Data)
 		if err != nil {
-			b.logger.Error("Event handler failed", zap.Error(err))
+			b.logger.Error("Event handler failed", zap.String("handler", handler.Name), zap.Error(err))
 		}
 	}
 
@@ -173,16 +381,22 @@ func (b *Brain) handle(ctx context.Context, evt event) {
 	}
 }
 
-func (b *Brain) executeHandler(ctx context.Context, handler eventHandler, event reflect.Value) error {
+func (b *Brain) executeHandler(ctx context.Context, handler HandlerFunc, event interface{}) error {
 	if b.handlerTimeout > 0 {
 		var cancel func()
 		ctx, cancel = context.WithTimeout(ctx, b.handlerTimeout)
 		defer cancel()
 	}
 
-	done := make(chan error)
+	done := make(chan error, 1)
 
 	go func() {
+		defer func() {
+			if err := recover(); err != nil {
+				done <- errors.Errorf("handler panic: %#v", err)
+			}
+		}()
+
 		done <- handler(ctx, event)
 	}()
 
@@ -190,15 +404,42 @@ func (b *Brain) executeHandler(ctx context.Context, handler eventHandler, event
 	case err := <-done:
 		return err
 	case <-ctx.Done():
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
 		return ctx.Err()
 	}
+
+	// The brain is shutting down. In-flight handlers may still drain until
+	// their own deadline.
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return <-done
+	}
+
+	drain, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	select {
+	case err := <-done:
+		return err
+	case <-drain.Done():
+		return drain.Err()
+	}
 }
 
-// Emit emits the new events
+// Emit emits the new events. Events are queued in the order Emit is called
+// and Emit never blocks.
 func (b *Brain) Emit(eventData interface{}, callbacks ...func(event)) {
-	go func() {
-		b.input <- event{Data: eventData, callbacks: callbacks}
-	}()
+	b.input.push(event{Data: eventData, callbacks: callbacks})
+}
+
+// EmitHandled emits the event like Emit and returns a channel which is
+// closed once all handlers of the event ran.
+func (b *Brain) EmitHandled(eventData interface{}) <-chan struct{} {
+	done := make(chan struct{})
+	b.Emit(eventData, func(event) { close(done) })
+	return done
 }
 
 // BotInput interface
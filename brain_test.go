@@ -0,0 +1,165 @@
+package zha
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestProcessConcurrentChannels(t *testing.T) {
+	brain := NewBrain(zap.NewNop(), time.Second)
+	brain.concurrency = 4
+
+	// find two channels that are handled by different workers
+	slow, fast := "C1", "C2"
+	for i := 2; workerIndex(slow, 4) == workerIndex(fast, 4); i++ {
+		fast = "C" + string(rune('0'+i))
+	}
+
+	slowStarted := make(chan struct{})
+	fastHandled := make(chan struct{})
+	brain.RegisterHandler(func(evt ReciveMessageEvent) {
+		if evt.ChannelD == fast {
+			close(fastHandled)
+			return
+		}
+
+		close(slowStarted)
+		select {
+		case <-fastHandled:
+		case <-time.After(time.Second):
+			t.Error("slow channel blocked the other channel")
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		brain.Process(ctx)
+		close(done)
+	}()
+
+	brain.Emit(ReciveMessageEvent{ChannelD: slow})
+	<-slowStarted
+	brain.Emit(ReciveMessageEvent{ChannelD: fast})
+
+	select {
+	case <-fastHandled:
+	case <-time.After(time.Second):
+		t.Error("event of the second channel was not handled")
+	}
+
+	cancel()
+	<-done
+}
+
+func TestProcessKeepsChannelOrder(t *testing.T) {
+	brain := NewBrain(zap.NewNop(), time.Second)
+	brain.concurrency = 4
+
+	expected := []string{"a", "b", "c", "d", "e"}
+
+	var mu sync.Mutex
+	var received []string
+	var handled sync.WaitGroup
+	handled.Add(len(expected))
+	brain.RegisterHandler(func(evt ReciveMessageEvent) {
+		mu.Lock()
+		received = append(received, evt.Text)
+		mu.Unlock()
+		handled.Done()
+	})
+
+	shutdown := make(chan struct{})
+	brain.RegisterHandler(func(ShutdownEvent) {
+		close(shutdown)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go brain.Process(ctx)
+
+	for _, text := range expected {
+		brain.Emit(ReciveMessageEvent{Text: text, ChannelD: "C1"})
+	}
+
+	handled.Wait()
+	cancel()
+	<-shutdown
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != len(expected) {
+		t.Fatalf("expected %d events, got %v", len(expected), received)
+	}
+	for i := range expected {
+		if received[i] != expected[i] {
+			t.Errorf("events are out of order %v", received)
+			break
+		}
+	}
+}
+
+func TestShutdownWaitsForInFlightHandlers(t *testing.T) {
+	brain := NewBrain(zap.NewNop(), time.Second)
+	brain.concurrency = 2
+
+	started := make(chan struct{})
+	var finished bool
+	var mu sync.Mutex
+	brain.RegisterHandler(func(ctx context.Context, evt ReciveMessageEvent) {
+		close(started)
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		finished = true
+		mu.Unlock()
+	})
+
+	shutdown := make(chan bool, 1)
+	brain.RegisterHandler(func(ShutdownEvent) {
+		mu.Lock()
+		shutdown <- finished
+		mu.Unlock()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go brain.Process(ctx)
+
+	brain.Emit(ReciveMessageEvent{ChannelD: "C1"})
+	<-started
+	cancel()
+
+	select {
+	case drained := <-shutdown:
+		if !drained {
+			t.Error("shutdown event was handled before in-flight handler finished")
+		}
+	case <-time.After(time.Second):
+		t.Error("shutdown event was not handled")
+	}
+}
+
+func TestEmitHandled(t *testing.T) {
+	brain := NewBrain(zap.NewNop(), time.Second)
+
+	var handled bool
+	brain.RegisterHandler(func(ReciveMessageEvent) {
+		handled = true
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go brain.Process(ctx)
+
+	select {
+	case <-brain.EmitHandled(ReciveMessageEvent{ChannelD: "C1"}):
+		if !handled {
+			t.Error("event was reported as handled before the handler ran")
+		}
+	case <-time.After(time.Second):
+		t.Error("event was not handled")
+	}
+}
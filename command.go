@@ -0,0 +1,125 @@
+package zha
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// CommandEvent is emitted when a user invokes a command, e.g. a slack
+// slash command.
+type CommandEvent struct {
+	// Name of the command without the leading slash.
+	Name     string
+	Text     string
+	UserID   string
+	ChannelD string
+	// ResponseURL is used to respond to the command after it was acknowledged.
+	ResponseURL string
+	// TriggerID allows to open dialogs in response to the command.
+	TriggerID string
+	// Raw is the adapter specific payload the command was created from.
+	Raw interface{}
+}
+
+// GetRoomID returns the channel the command was invoked in.
+func (e CommandEvent) GetRoomID() string {
+	return e.ChannelD
+}
+
+// CommandResponse is a delayed response to a command.
+type CommandResponse struct {
+	ResponseURL string
+	Text        string
+	// InChannel makes the response visible to everyone in the channel,
+	// otherwise only the user who invoked the command sees it.
+	InChannel bool
+}
+
+// CommandResponder is implemented by adapters which can respond to commands.
+type CommandResponder interface {
+	RespondCommand(CommandResponse) error
+}
+
+// Command is passed to command handlers.
+type Command struct {
+	Context     context.Context
+	Name        string
+	Text        string
+	UserID      string
+	ChannelD    string
+	ResponseURL string
+	TriggerID   string
+	Raw         interface{}
+
+	adapter Adapter
+}
+
+// Respond sends a response only visible to the user who invoked the command.
+func (cmd *Command) Respond(text string, args ...interface{}) error {
+	return cmd.respond(format(text, args), false)
+}
+
+// RespondInChannel sends a response visible to everyone in the channel.
+func (cmd *Command) RespondInChannel(text string, args ...interface{}) error {
+	return cmd.respond(format(text, args), true)
+}
+
+func (cmd *Command) respond(text string, inChannel bool) error {
+	responder, ok := cmd.adapter.(CommandResponder)
+	if !ok {
+		return errors.New("adapter can not respond to commands")
+	}
+
+	return responder.RespondCommand(CommandResponse{
+		ResponseURL: cmd.ResponseURL,
+		Text:        text,
+		InChannel:   inChannel,
+	})
+}
+
+// Command registers a handler for the command with the given name, with or
+// without the leading slash. The given middleware runs only for this command.
+func (b *Bot) Command(name string, fun func(Command) error, mws ...Middleware) (*Handle, error) {
+	if err := b.strictErr(); err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimPrefix(strings.TrimSpace(name), "/")
+	handlerName := "command: /" + name
+
+	if name == "" {
+		err := &RegistrationError{Handler: handlerName, Err: errors.New("command name is empty")}
+		b.Reject(err)
+		return nil, err
+	}
+
+	if fun == nil {
+		err := &RegistrationError{Handler: handlerName, Err: errors.New("command handler is nil")}
+		b.Reject(err)
+		return nil, err
+	}
+
+	return b.Brain.registerHandler(func(ctx context.Context, evt CommandEvent) error {
+		if !strings.EqualFold(evt.Name, name) {
+			return nil
+		}
+
+		handler := func(ctx context.Context, _ interface{}) error {
+			return fun(Command{
+				Context:     ctx,
+				Name:        evt.Name,
+				Text:        evt.Text,
+				UserID:      evt.UserID,
+				ChannelD:    evt.ChannelD,
+				ResponseURL: evt.ResponseURL,
+				TriggerID:   evt.TriggerID,
+				Raw:         evt.Raw,
+				adapter:     b.Adapter,
+			})
+		}
+
+		return b.Brain.Chain(handler, mws...)(ctx, evt)
+	}, handlerName, nil, true)
+}
@@ -0,0 +1,60 @@
+package zha
+
+import (
+	"context"
+	"testing"
+)
+
+type commandAdapter struct {
+	nopAdapter
+	responses []CommandResponse
+}
+
+func (a *commandAdapter) RespondCommand(response CommandResponse) error {
+	a.responses = append(a.responses, response)
+	return nil
+}
+
+func TestCommand(t *testing.T) {
+	bot := newTestBot()
+	adapter := &commandAdapter{}
+	bot.Adapter = adapter
+
+	bot.Command("/deploy", func(cmd Command) error {
+		if err := cmd.Respond("deploying %s", cmd.Text); err != nil {
+			return err
+		}
+
+		return cmd.RespondInChannel("deployed %s", cmd.Text)
+	})
+
+	bot.Brain.handle(context.Background(), event{Data: CommandEvent{Name: "other", Text: "api"}})
+	bot.Brain.handle(context.Background(), event{Data: CommandEvent{Name: "Deploy", Text: "api", ResponseURL: "https://localhost/response"}})
+
+	expected := []CommandResponse{
+		{ResponseURL: "https://localhost/response", Text: "deploying api"},
+		{ResponseURL: "https://localhost/response", Text: "deployed api", InChannel: true},
+	}
+	if len(adapter.responses) != len(expected) {
+		t.Fatalf("expected responses %#v, got %#v", expected, adapter.responses)
+	}
+	for i := range expected {
+		if adapter.responses[i] != expected[i] {
+			t.Errorf("expected %#v, got %#v", expected[i], adapter.responses[i])
+		}
+	}
+}
+
+func TestCommandRegistrationErrors(t *testing.T) {
+	bot := newTestBot()
+
+	if _, err := bot.Command("/", func(Command) error { return nil }); err == nil {
+		t.Error("expected error for empty command name")
+	}
+	if _, err := bot.Command("/deploy", nil); err == nil {
+		t.Error("expected error for nil handler")
+	}
+	if len(bot.RejectedRegistrations()) != 2 {
+		t.Errorf("expected two rejected registrations, got %#v", bot.RejectedRegistrations())
+	}
+}
@@ -0,0 +1,40 @@
+package zha
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RegistrationError is returned when a handler can not be registered.
+type RegistrationError struct {
+	Handler string
+	Err     error
+}
+
+// Error returns the error string
+func (e *RegistrationError) Error() string {
+	return fmt.Sprintf("failed to register handler %q: %s", e.Handler, e.Err)
+}
+
+// Cause returns the underlying error
+func (e *RegistrationError) Cause() error {
+	return e.Err
+}
+
+// Unwrap returns the underlying error
+func (e *RegistrationError) Unwrap() error {
+	return e.Err
+}
+
+// RegistrationErrors is a report of every rejected registration.
+type RegistrationErrors []*RegistrationError
+
+// Error returns the error string
+func (e RegistrationErrors) Error() string {
+	errs := make([]string, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err.Error())
+	}
+
+	return strings.Join(errs, "\n")
+}
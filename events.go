@@ -1,5 +1,7 @@
 package zha
 
+import "time"
+
 // InitEvent struct
 type InitEvent struct{}
 
@@ -10,4 +12,121 @@ type ShutdownEvent struct{}
 type ReciveMessageEvent struct {
 	Text     string
 	ChannelD string
+	// UserID is the identifier of the sender.
+	UserID string
+	// SentAt is the time the message was sent at.
+	SentAt time.Time
+	// ID identifies the message within its channel, e.g. the slack timestamp.
+	ID string
+	// ThreadID is the ID of the parent message if the message was sent in a thread.
+	ThreadID string
+	// FromSelf is set when the message was sent by the bot itself.
+	FromSelf bool
+	// FromBot is set when the message was sent by a bot, e.g. an integration.
+	FromBot bool
+	// Subtype tells adapter specific kinds of messages apart, e.g. slack
+	// thread broadcasts or /me messages. It is empty for plain messages.
+	Subtype string
+	// Direct is set when the message was sent in a direct message channel.
+	Direct bool
+	// Raw is the adapter specific event the message was created from.
+	Raw interface{}
+}
+
+// GetRoomID returns the channel the message was sent to.
+func (e ReciveMessageEvent) GetRoomID() string {
+	return e.ChannelD
+}
+
+// MessageChangedEvent is emitted when a message is edited.
+type MessageChangedEvent struct {
+	ChannelID string
+	// ID identifies the edited message.
+	ID       string
+	ThreadID string
+	UserID   string
+	// Text is the new text of the message.
+	Text string
+	// PreviousText is the text before the edit, if the adapter knows it.
+	PreviousText string
+	FromSelf     bool
+	FromBot      bool
+	Raw          interface{}
+}
+
+// GetRoomID returns the channel of the message.
+func (e MessageChangedEvent) GetRoomID() string {
+	return e.ChannelID
+}
+
+// MessageDeletedEvent is emitted when a message is deleted.
+type MessageDeletedEvent struct {
+	ChannelID string
+	// ID identifies the deleted message.
+	ID string
+	// UserID and PreviousText describe the deleted message, if the adapter
+	// knows it.
+	UserID       string
+	PreviousText string
+	FromSelf     bool
+	FromBot      bool
+	Raw          interface{}
+}
+
+// GetRoomID returns the channel of the message.
+func (e MessageDeletedEvent) GetRoomID() string {
+	return e.ChannelID
+}
+
+// ChannelJoinEvent is emitted when a user joins a channel.
+type ChannelJoinEvent struct {
+	ChannelID string
+	UserID    string
+	// InviterID is the user who invited the user, if any.
+	InviterID string
+	FromSelf  bool
+	Raw       interface{}
+}
+
+// GetRoomID returns the joined channel.
+func (e ChannelJoinEvent) GetRoomID() string {
+	return e.ChannelID
+}
+
+// ChannelEvent is implemented by events which belong to a channel.
+// The Brain handles events of the same channel in the order they are emitted.
+type ChannelEvent interface {
+	GetRoomID() string
+}
+
+// Reaction describes an emoji reaction to a message.
+type Reaction struct {
+	// Emoji is the name of the emoji, without colons.
+	Emoji     string
+	ChannelID string
+	// MessageID identifies the message the reaction belongs to.
+	MessageID string
+	// UserID is the identifier of the user who reacted.
+	UserID string
+	// ItemUserID is the identifier of the author of the message.
+	ItemUserID string
+	// FromSelf is set when the bot itself reacted.
+	FromSelf bool
+	// Raw is the adapter specific event the reaction was created from.
+	Raw interface{}
+}
+
+// GetRoomID returns the channel of the message.
+func (r Reaction) GetRoomID() string {
+	return r.ChannelID
+}
+
+// ReactionAddedEvent is emitted when a user adds a reaction to a message.
+type ReactionAddedEvent struct {
+	Reaction
+}
+
+// ReactionRemovedEvent is emitted when a user removes a reaction from a message.
+type ReactionRemovedEvent struct {
+	Reaction
 }
@@ -0,0 +1,29 @@
+package zha
+
+import "reflect"
+
+// HandlerInfo describes a registered handler.
+type HandlerInfo struct {
+	ID        uint64
+	EventType reflect.Type
+	Name      string
+}
+
+// Handle is returned when a handler is registered and can be used to
+// unregister it again, also while the brain is processing events.
+type Handle struct {
+	brain *Brain
+	info  HandlerInfo
+}
+
+// Info returns the description of the registered handler.
+func (h *Handle) Info() HandlerInfo {
+	return h.info
+}
+
+// Unregister removes the handler from the brain. Events which are already
+// being handled are not interrupted. It returns false if the handler was
+// already unregistered.
+func (h *Handle) Unregister() bool {
+	return h.brain.unregister(h.info.EventType, h.info.ID)
+}
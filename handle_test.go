@@ -0,0 +1,84 @@
+package zha
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestHandleUnregister(t *testing.T) {
+	brain := NewBrain(zap.NewNop(), time.Second)
+
+	calls := 0
+	handle, _ := brain.RegisterHandler(func(ReciveMessageEvent) {
+		calls++
+	})
+	if handle == nil {
+		t.Fatal("expected handle to be returned")
+	}
+
+	brain.handle(context.Background(), event{Data: ReciveMessageEvent{}})
+	if !handle.Unregister() {
+		t.Error("expected handler to be unregistered")
+	}
+	if handle.Unregister() {
+		t.Error("handler can not be unregistered twice")
+	}
+	brain.handle(context.Background(), event{Data: ReciveMessageEvent{}})
+
+	if calls != 1 {
+		t.Errorf("expected one call, got %d", calls)
+	}
+}
+
+func TestHandlers(t *testing.T) {
+	brain := NewBrain(zap.NewNop(), time.Second)
+
+	first, _ := brain.RegisterHandler(func(ReciveMessageEvent) {})
+	brain.RegisterHandler(func(ReciveMessageEvent) {})
+	brain.RegisterHandler(func(InitEvent) {})
+
+	handlers := brain.Handlers()
+	msgType := reflect.TypeOf(ReciveMessageEvent{})
+	if len(handlers[msgType]) != 2 {
+		t.Errorf("expected two message handlers, got %#v", handlers[msgType])
+	}
+	if len(handlers[reflect.TypeOf(InitEvent{})]) != 1 {
+		t.Errorf("expected one init handler, got %#v", handlers)
+	}
+	if handlers[msgType][0] != first.Info() {
+		t.Errorf("unexpected handler info %#v", handlers[msgType][0])
+	}
+
+	first.Unregister()
+	if len(brain.Handlers()[msgType]) != 1 {
+		t.Errorf("expected one message handler after unregister, got %#v", brain.Handlers())
+	}
+}
+
+func TestUnregisterWhileHandling(t *testing.T) {
+	brain := NewBrain(zap.NewNop(), time.Second)
+
+	var second *Handle
+	secondCalled := false
+	brain.RegisterHandler(func(ReciveMessageEvent) {
+		second.Unregister()
+	})
+	second, _ = brain.RegisterHandler(func(ReciveMessageEvent) {
+		secondCalled = true
+	})
+
+	brain.handle(context.Background(), event{Data: ReciveMessageEvent{}})
+	if !secondCalled {
+		t.Error("handler of the current event should still be called")
+	}
+
+	secondCalled = false
+	brain.handle(context.Background(), event{Data: ReciveMessageEvent{}})
+	if secondCalled {
+		t.Error("unregistered handler was called")
+	}
+}
@@ -3,6 +3,11 @@ package zha
 import (
 	"context"
 	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
 // Message struct
@@ -11,15 +16,246 @@ type Message struct {
 	Text     string
 	ChannelD string
 	Matches  []string
+	UserID   string
+	SentAt   time.Time
+	ID       string
+	ThreadID string
+	FromSelf bool
+	FromBot  bool
+	Subtype  string
+	Direct   bool
+	Raw      interface{}
+
+	adapter  Adapter
+	threaded bool
+}
+
+// OutgoingMessage is a message sent by the bot.
+type OutgoingMessage struct {
+	ChannelID string
+	Text      string
+	// ThreadID makes the message a reply in the thread of the given message.
+	ThreadID string
+	// Blocks are adapter specific rich layout blocks, e.g. slack Block Kit
+	// blocks. Adapters which do not support blocks only send the Text, so it
+	// should work as a fallback.
+	Blocks []interface{}
+}
+
+// SentMessage describes a message which was sent by the bot.
+type SentMessage struct {
+	ChannelID string
+	// ID identifies the posted message, it is empty if the adapter can not tell.
+	ID string
+}
+
+// MessageSender is implemented by adapters which can send outgoing messages
+// with more options than Adapter.Send, e.g. as replies in a thread.
+type MessageSender interface {
+	SendMessage(OutgoingMessage) (*SentMessage, error)
+}
+
+// OutgoingFile is a file or a snippet sent by the bot.
+type OutgoingFile struct {
+	ChannelID string
+	// ThreadID shares the file in the thread of the given message.
+	ThreadID string
+	Filename string
+	Title    string
+	// Comment is sent along with the file.
+	Comment string
+	Content io.Reader
+	// Length is the size of the content. If it is known the content is
+	// streamed to the chat service instead of being read into memory.
+	Length int64
+	// Snippet shares the content as a text snippet instead of a file.
+	Snippet bool
+}
+
+// FileSender is implemented by adapters which can upload files.
+type FileSender interface {
+	SendFile(OutgoingFile) (*SentMessage, error)
+}
+
+// Reactor is implemented by adapters which can add emoji reactions to
+// messages.
+type Reactor interface {
+	React(channelID, messageID, emoji string) error
+}
+
+// MessageEditor is implemented by adapters which can edit and delete
+// messages sent by the bot.
+type MessageEditor interface {
+	EditMessage(sent SentMessage, text string) error
+	DeleteMessage(sent SentMessage) error
+}
+
+// Respond sends a message to the channel of the message. With the
+// WithThreadedReplies option the message is sent as a reply in its thread.
+func (msg *Message) Respond(text string, args ...interface{}) error {
+	_, err := msg.Send(msg.response(format(text, args)))
+	return err
+}
+
+// RespondWithBlocks sends a message with layout blocks to the channel of the
+// message. The text is shown where the blocks can not be displayed, e.g. in
+// notifications.
+func (msg *Message) RespondWithBlocks(text string, blocks ...interface{}) error {
+	out := msg.response(text)
+	out.Blocks = blocks
+
+	_, err := msg.Send(out)
+	return err
+}
+
+// ReplyInThread sends a reply in the thread of the message. If the message
+// does not belong to a thread yet, a new thread is started from it.
+func (msg *Message) ReplyInThread(text string, args ...interface{}) error {
+	_, err := msg.Send(OutgoingMessage{
+		ChannelID: msg.ChannelD,
+		Text:      format(text, args),
+		ThreadID:  msg.threadRoot(),
+	})
+	return err
+}
+
+// Reply responds to the message mentioning its sender.
+func (msg *Message) Reply(text string, args ...interface{}) error {
+	mention := "@" + msg.UserID
+	if addresser, ok := msg.adapter.(Addresser); ok {
+		mention = addresser.Mention(msg.UserID)
+	}
+
+	return msg.Respond("%s %s", mention, format(text, args))
+}
+
+// Send sends the outgoing message through the adapter and returns the sent
+// message, e.g. to edit or reply to it later.
+func (msg *Message) Send(out OutgoingMessage) (*SentMessage, error) {
+	if sender, ok := msg.adapter.(MessageSender); ok {
+		return sender.SendMessage(out)
+	}
+
+	if err := msg.adapter.Send(out.Text, out.ChannelID); err != nil {
+		return nil, err
+	}
+
+	return &SentMessage{ChannelID: out.ChannelID}, nil
+}
+
+// AttachFile uploads the content as a file to the channel of the message.
+// With the WithThreadedReplies option the file is shared in its thread.
+func (msg *Message) AttachFile(filename string, content io.Reader, comment string) error {
+	return msg.sendFile(filename, content, comment, false)
+}
+
+// Snippet shares the text as a snippet in the channel of the message.
+func (msg *Message) Snippet(filename, text, comment string) error {
+	return msg.sendFile(filename, strings.NewReader(text), comment, true)
+}
+
+func (msg *Message) sendFile(filename string, content io.Reader, comment string, snippet bool) error {
+	sender, ok := msg.adapter.(FileSender)
+	if !ok {
+		return errors.New("adapter can not send files")
+	}
+
+	out := msg.response(comment)
+	_, err := sender.SendFile(OutgoingFile{
+		ChannelID: out.ChannelID,
+		ThreadID:  out.ThreadID,
+		Filename:  filename,
+		Title:     filename,
+		Comment:   comment,
+		Content:   content,
+		Length:    lengthOf(content),
+		Snippet:   snippet,
+	})
+	return err
+}
+
+// lengthOf returns the size of in-memory content such as a strings.Reader,
+// bytes.Reader or bytes.Buffer, or 0 if it is not known.
+func lengthOf(content io.Reader) int64 {
+	if sized, ok := content.(interface{ Len() int }); ok {
+		return int64(sized.Len())
+	}
+
+	return 0
+}
+
+// React adds the emoji reaction to the message, e.g. to acknowledge a command
+// without replying.
+func (msg *Message) React(emoji string) error {
+	reactor, ok := msg.adapter.(Reactor)
+	if !ok {
+		return errors.New("adapter can not react to messages")
+	}
+
+	if msg.ID == "" {
+		return errors.New("message has no ID")
+	}
+
+	return reactor.React(msg.ChannelD, msg.ID, emoji)
+}
+
+// Edit replaces the text of a message the bot sent, e.g. a reply returned
+// by Send.
+func (msg *Message) Edit(sent *SentMessage, text string, args ...interface{}) error {
+	editor, err := msg.editor(sent)
+	if err != nil {
+		return err
+	}
+
+	return editor.EditMessage(*sent, format(text, args))
+}
+
+// Delete deletes a message the bot sent, e.g. a reply returned by Send.
+func (msg *Message) Delete(sent *SentMessage) error {
+	editor, err := msg.editor(sent)
+	if err != nil {
+		return err
+	}
+
+	return editor.DeleteMessage(*sent)
+}
+
+func (msg *Message) editor(sent *SentMessage) (MessageEditor, error) {
+	editor, ok := msg.adapter.(MessageEditor)
+	if !ok {
+		return nil, errors.New("adapter can not edit messages")
+	}
+
+	if sent == nil || sent.ID == "" {
+		return nil, errors.New("sent message has no ID")
+	}
+
+	return editor, nil
+}
+
+// response returns an outgoing message answering this message, which is sent
+// into its thread if threaded replies are enabled.
+func (msg *Message) response(text string) OutgoingMessage {
+	out := OutgoingMessage{ChannelID: msg.ChannelD, Text: text}
+	if msg.threaded {
+		out.ThreadID = msg.threadRoot()
+	}
+
+	return out
+}
+
+func (msg *Message) threadRoot() string {
+	if msg.ThreadID != "" {
+		return msg.ThreadID
+	}
 
-	adapter Adapter
+	return msg.ID
 }
 
-// Respond test
-func (msg *Message) Respond(text string, args ...interface{}) {
+func format(text string, args []interface{}) string {
 	if len(args) > 0 {
-		text = fmt.Sprintf(text, args...)
+		return fmt.Sprintf(text, args...)
 	}
 
-	_ = msg.adapter.Send(text, msg.ChannelD)
+	return text
 }
@@ -0,0 +1,182 @@
+package zha
+
+import (
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type recordingAdapter struct {
+	addressAdapter
+	sent   []OutgoingMessage
+	edited []string
+	files  []OutgoingFile
+	reacts []string
+}
+
+func (a *recordingAdapter) SendMessage(msg OutgoingMessage) (*SentMessage, error) {
+	if msg.ChannelID == "" {
+		return nil, errors.New("channel is missing")
+	}
+
+	a.sent = append(a.sent, msg)
+	return &SentMessage{ChannelID: msg.ChannelID, ID: "9.0"}, nil
+}
+
+func (a *recordingAdapter) SendFile(file OutgoingFile) (*SentMessage, error) {
+	a.files = append(a.files, file)
+	return &SentMessage{ChannelID: file.ChannelID}, nil
+}
+
+func (a *recordingAdapter) React(channelID, messageID, emoji string) error {
+	a.reacts = append(a.reacts, channelID+"/"+messageID+": "+emoji)
+	return nil
+}
+
+func (a *recordingAdapter) EditMessage(sent SentMessage, text string) error {
+	a.edited = append(a.edited, sent.ID+": "+text)
+	return nil
+}
+
+func (a *recordingAdapter) DeleteMessage(sent SentMessage) error {
+	a.edited = append(a.edited, sent.ID+": deleted")
+	return nil
+}
+
+func TestMessageReplies(t *testing.T) {
+	adapter := &recordingAdapter{}
+	msg := Message{ChannelD: "C1", ID: "2.0", UserID: "U1", adapter: adapter}
+
+	msg.Respond("hello %s", "world")
+	msg.ReplyInThread("in thread")
+	msg.Reply("hi")
+
+	threaded := Message{ChannelD: "C1", ID: "3.0", ThreadID: "1.0", adapter: adapter, threaded: true}
+	threaded.Respond("default thread")
+	msg.RespondWithBlocks("fallback", "block")
+
+	expected := []OutgoingMessage{
+		{ChannelID: "C1", Text: "hello world"},
+		{ChannelID: "C1", Text: "in thread", ThreadID: "2.0"},
+		{ChannelID: "C1", Text: "<@U1> hi"},
+		{ChannelID: "C1", Text: "default thread", ThreadID: "1.0"},
+		{ChannelID: "C1", Text: "fallback", Blocks: []interface{}{"block"}},
+	}
+
+	if len(adapter.sent) != len(expected) {
+		t.Fatalf("expected %d messages, got %#v", len(expected), adapter.sent)
+	}
+	for i := range expected {
+		if !reflect.DeepEqual(adapter.sent[i], expected[i]) {
+			t.Errorf("expected %#v, got %#v", expected[i], adapter.sent[i])
+		}
+	}
+}
+
+func TestMessageSendErrors(t *testing.T) {
+	adapter := &recordingAdapter{}
+
+	msg := Message{ChannelD: "C1", adapter: adapter}
+	sent, err := msg.Send(OutgoingMessage{ChannelID: "C1", Text: "hi"})
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+	if sent.ID != "9.0" {
+		t.Errorf("unexpected sent message %#v", sent)
+	}
+
+	broken := Message{adapter: adapter}
+	if err := broken.Respond("hi"); err == nil {
+		t.Error("expected send error to be returned")
+	}
+}
+
+func TestMessageEdit(t *testing.T) {
+	adapter := &recordingAdapter{}
+	msg := Message{ChannelD: "C1", adapter: adapter}
+
+	sent, err := msg.Send(OutgoingMessage{ChannelID: "C1", Text: "deploying"})
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if err := msg.Edit(sent, "deployed %s", "api"); err != nil {
+		t.Errorf("unexpected edit error %#v", err)
+	}
+	if err := msg.Delete(sent); err != nil {
+		t.Errorf("unexpected delete error %#v", err)
+	}
+
+	expected := []string{"9.0: deployed api", "9.0: deleted"}
+	if !reflect.DeepEqual(adapter.edited, expected) {
+		t.Errorf("expected %v, got %v", expected, adapter.edited)
+	}
+
+	if err := msg.Edit(&SentMessage{ChannelID: "C1"}, "lost"); err == nil {
+		t.Error("expected error for a message without ID")
+	}
+
+	unsupported := Message{ChannelD: "C1", adapter: nopAdapter{}}
+	if err := unsupported.Delete(sent); err == nil {
+		t.Error("expected error for an adapter which can not edit messages")
+	}
+}
+
+func TestMessageAttachFile(t *testing.T) {
+	adapter := &recordingAdapter{}
+	msg := Message{ChannelD: "C1", ID: "3.0", adapter: adapter, threaded: true}
+
+	if err := msg.AttachFile("report.csv", strings.NewReader("a,b"), "the report"); err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+	if err := msg.Snippet("main.go", "package main", ""); err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if len(adapter.files) != 2 {
+		t.Fatalf("expected 2 files, got %#v", adapter.files)
+	}
+
+	report := adapter.files[0]
+	content, _ := ioutil.ReadAll(report.Content)
+	if report.ChannelID != "C1" || report.ThreadID != "3.0" || report.Filename != "report.csv" ||
+		report.Title != "report.csv" || report.Comment != "the report" || report.Length != 3 ||
+		report.Snippet || string(content) != "a,b" {
+		t.Errorf("unexpected file %#v", report)
+	}
+
+	if snippet := adapter.files[1]; !snippet.Snippet || snippet.Filename != "main.go" {
+		t.Errorf("unexpected snippet %#v", snippet)
+	}
+
+	unsupported := Message{ChannelD: "C1", adapter: nopAdapter{}}
+	if err := unsupported.Snippet("main.go", "package main", ""); err == nil {
+		t.Error("expected error for an adapter which can not send files")
+	}
+}
+
+func TestMessageReact(t *testing.T) {
+	adapter := &recordingAdapter{}
+	msg := Message{ChannelD: "C1", ID: "2.0", adapter: adapter}
+
+	if err := msg.React("white_check_mark"); err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	expected := []string{"C1/2.0: white_check_mark"}
+	if !reflect.DeepEqual(adapter.reacts, expected) {
+		t.Errorf("expected %v, got %v", expected, adapter.reacts)
+	}
+
+	unknown := Message{ChannelD: "C1", adapter: adapter}
+	if err := unknown.React("eyes"); err == nil {
+		t.Error("expected error for a message without ID")
+	}
+
+	unsupported := Message{ChannelD: "C1", ID: "2.0", adapter: nopAdapter{}}
+	if err := unsupported.React("eyes"); err == nil {
+		t.Error("expected error for an adapter which can not react")
+	}
+}
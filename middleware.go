@@ -0,0 +1,28 @@
+package zha
+
+import "context"
+
+// HandlerFunc is the uniform shape of every registered handler.
+type HandlerFunc func(ctx context.Context, evt interface{}) error
+
+// Middleware wraps a HandlerFunc. A middleware can inspect the event,
+// decorate the context passed to next, short-circuit by not calling next
+// at all and observe the error returned by next.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// chainMiddleware wraps handler so the first middleware is the outermost one.
+func chainMiddleware(handler HandlerFunc, mws []Middleware) HandlerFunc {
+	for i := len(mws) - 1; i >= 0; i-- {
+		handler = mws[i](handler)
+	}
+
+	return handler
+}
+
+// Chain wraps the handler with the middleware, the first middleware is the
+// outermost one. It allows handlers registered outside of this package to
+// apply their middleware only to the events they are interested in.
+// Brain.Chain also applies the middleware added with Use.
+func Chain(handler HandlerFunc, mws ...Middleware) HandlerFunc {
+	return chainMiddleware(handler, mws)
+}
@@ -0,0 +1,151 @@
+package zha
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type ctxKey string
+
+func TestMiddlewareOrder(t *testing.T) {
+	brain := NewBrain(zap.NewNop(), time.Second)
+
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(ctx context.Context, evt interface{}) error {
+				calls = append(calls, name)
+				return next(ctx, evt)
+			}
+		}
+	}
+
+	brain.Use(trace("global-1"), trace("global-2"))
+	brain.RegisterHandler(func(ReciveMessageEvent) {
+		calls = append(calls, "handler")
+	}, trace("local"))
+
+	brain.handle(context.Background(), event{Data: ReciveMessageEvent{}})
+
+	expected := []string{"global-1", "global-2", "local", "handler"}
+	if len(calls) != len(expected) {
+		t.Fatalf("unexpected calls %v", calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("unexpected call order %v", calls)
+			break
+		}
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	brain := NewBrain(zap.NewNop(), time.Second)
+
+	brain.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, evt interface{}) error {
+			if msg, ok := evt.(ReciveMessageEvent); ok && msg.ChannelD == "forbidden" {
+				return nil
+			}
+
+			return next(ctx, evt)
+		}
+	})
+
+	handled := 0
+	brain.RegisterHandler(func(ReciveMessageEvent) {
+		handled++
+	})
+
+	brain.handle(context.Background(), event{Data: ReciveMessageEvent{ChannelD: "forbidden"}})
+	brain.handle(context.Background(), event{Data: ReciveMessageEvent{ChannelD: "allowed"}})
+
+	if handled != 1 {
+		t.Errorf("expected handler to be called once, was called %d times", handled)
+	}
+}
+
+func TestMiddlewareContextAndError(t *testing.T) {
+	brain := NewBrain(zap.NewNop(), time.Second)
+
+	handlerErr := errors.New("failed")
+	var observed error
+	brain.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, evt interface{}) error {
+			observed = next(context.WithValue(ctx, ctxKey("user"), "U123"), evt)
+			return observed
+		}
+	})
+
+	var user interface{}
+	brain.RegisterHandler(func(ctx context.Context, evt ReciveMessageEvent) error {
+		user = ctx.Value(ctxKey("user"))
+		return handlerErr
+	})
+
+	brain.handle(context.Background(), event{Data: ReciveMessageEvent{}})
+
+	if user != "U123" {
+		t.Errorf("context was not decorated, got %v", user)
+	}
+	if observed != handlerErr {
+		t.Errorf("middleware did not observe handler error, got %v", observed)
+	}
+}
+
+func TestMiddlewareObservesPanic(t *testing.T) {
+	brain := NewBrain(zap.NewNop(), time.Second)
+
+	var observed error
+	brain.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, evt interface{}) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					observed = errors.New("recovered")
+					panic(r)
+				}
+			}()
+
+			return next(ctx, evt)
+		}
+	})
+
+	brain.RegisterHandler(func(ReciveMessageEvent) {
+		panic("boom")
+	})
+
+	brain.handle(context.Background(), event{Data: ReciveMessageEvent{}})
+
+	if observed == nil {
+		t.Error("middleware did not observe the panic")
+	}
+}
+
+func TestMiddlewareRunsOnlyForMatchedHandlers(t *testing.T) {
+	bot := newTestBot()
+
+	calls := 0
+	bot.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, evt interface{}) error {
+			calls++
+			return next(ctx, evt)
+		}
+	})
+
+	bot.Respond("deploy", func(Message) error { return nil })
+	bot.Respond("rollback", func(Message) error { return nil })
+
+	bot.Brain.handle(context.Background(), event{Data: ReciveMessageEvent{Text: "zha status"}})
+	if calls != 0 {
+		t.Errorf("expected no middleware calls for an unmatched message, got %d", calls)
+	}
+
+	bot.Brain.handle(context.Background(), event{Data: ReciveMessageEvent{Text: "zha deploy"}})
+	if calls != 1 {
+		t.Errorf("expected one middleware call for a matched message, got %d", calls)
+	}
+}
@@ -1,5 +1,7 @@
 package zha
 
+import "github.com/pkg/errors"
+
 // Option type
 type Option func(*Bot) error
 
@@ -10,3 +12,35 @@ func WithMemory(memory Memory) Option {
 		return nil
 	}
 }
+
+// WithConcurrency sets the number of workers processing events in parallel.
+// Events of the same channel are still processed one after the other.
+func WithConcurrency(workers int) Option {
+	return func(b *Bot) error {
+		if workers < 1 {
+			return errors.Errorf("concurrency must be at least 1, got %d", workers)
+		}
+
+		b.Brain.concurrency = workers
+		return nil
+	}
+}
+
+// WithStrictRegistration makes the bot fail fast on the first failed
+// handler registration. Later registrations return the same error without
+// registering the handler, and Run returns it.
+func WithStrictRegistration() Option {
+	return func(b *Bot) error {
+		b.strict = true
+		return nil
+	}
+}
+
+// WithThreadedReplies makes Message.Respond reply in the thread of the
+// message instead of sending a new message to the channel.
+func WithThreadedReplies() Option {
+	return func(b *Bot) error {
+		b.threadedReplies = true
+		return nil
+	}
+}
@@ -0,0 +1,67 @@
+package slack
+
+import (
+	"sync"
+
+	"gitlab.com/kochevRisto/go-zha/slack/rtmapi"
+)
+
+// ack is the outcome of a message sent over the RTM websocket.
+type ack struct {
+	reply *rtmapi.WebSocketReply
+	err   error
+}
+
+// pendingAcks correlates the replies from slack with the sent messages by
+// their outgoing event ID.
+type pendingAcks struct {
+	mu    sync.Mutex
+	sends map[uint]chan ack
+}
+
+func newPendingAcks() *pendingAcks {
+	return &pendingAcks{sends: map[uint]chan ack{}}
+}
+
+// register registers a sent message whose ack is delivered to done, which
+// has to be buffered.
+func (p *pendingAcks) register(id uint, done chan ack) {
+	p.mu.Lock()
+	p.sends[id] = done
+	p.mu.Unlock()
+}
+
+// remove forgets a sent message, e.g. after it timed out.
+func (p *pendingAcks) remove(id uint) {
+	p.mu.Lock()
+	delete(p.sends, id)
+	p.mu.Unlock()
+}
+
+// resolve delivers the ack of the sent message with the given ID. It returns
+// false if no message is waiting for it.
+func (p *pendingAcks) resolve(id uint, result ack) bool {
+	p.mu.Lock()
+	done, ok := p.sends[id]
+	delete(p.sends, id)
+	p.mu.Unlock()
+
+	if ok {
+		done <- result
+	}
+
+	return ok
+}
+
+// failAll resolves every pending message with the error, e.g. when the
+// connection is closed before slack replied.
+func (p *pendingAcks) failAll(err error) {
+	p.mu.Lock()
+	sends := p.sends
+	p.sends = map[uint]chan ack{}
+	p.mu.Unlock()
+
+	for _, done := range sends {
+		done <- ack{err: err}
+	}
+}
@@ -1,13 +1,20 @@
 package slack
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
 	"time"
 
 	"gitlab.com/kochevRisto/go-zha"
 	"gitlab.com/kochevRisto/go-zha/slack/retry"
 	"gitlab.com/kochevRisto/go-zha/slack/rtmapi"
+	"gitlab.com/kochevRisto/go-zha/slack/socketmode"
 	"gitlab.com/kochevRisto/go-zha/slack/webapi"
 	"go.uber.org/zap"
 	"golang.org/x/net/websocket"
@@ -17,21 +24,70 @@ import (
 type Config struct {
 	Token  string
 	Logger *zap.Logger
+	// WebAPIMessages sends messages with chat.postMessage instead of the RTM websocket.
+	WebAPIMessages bool
+	// SendTimeout is how long to wait for slack to acknowledge a RTM message,
+	// not counting the time it waits in the queue.
+	SendTimeout time.Duration
+	// SocketMode receives events over a Socket Mode connection instead of RTM.
+	// It requires an app-level token and sends messages with the web API.
+	SocketMode bool
+	AppToken   string
+	// EventsAPI receives events with HTTP requests from slack instead of RTM.
+	// Requests are verified with the signing secret and served on ListenAddr,
+	// or wherever HTTPHandler is mounted.
+	EventsAPI     bool
+	SigningSecret string
+	ListenAddr    string
+	// CommandAck is the ephemeral text slash commands are acknowledged with.
+	CommandAck string
+	// QueueSize is how many RTM messages may wait to be sent, messages are
+	// rejected with ErrQueueFull when the queue is full.
+	QueueSize int
+	// WebAPIOptions configure the web API clients, e.g. the HTTP client.
+	WebAPIOptions []webapi.Option
+	// OwnMessages passes the messages the bot sent itself on to the brain,
+	// e.g. for Hear handlers with IncludeSelf. They are ignored by default.
+	OwnMessages bool
+	// BotMessages passes messages of other bots on to the brain. They are
+	// ignored by default.
+	BotMessages bool
 }
 
+const defaultSendTimeout = 10 * time.Second
+
 // Adapter struct
 type Adapter struct {
 	WebAPIClient        *webapi.Client
 	RtmAPIClient        *rtmapi.Client
+	AppWebAPIClient     *webapi.Client
+	SocketModeClient    *socketmode.Client
 	tryPing             chan bool
 	Events              chan rtmapi.DecodedEvent
 	outgoingEventID     *rtmapi.OutgoingEventID
-	OutgoingMessages    chan *rtmapi.TextMessage
 	StartNewRtm         chan bool
 	webSocketConnection *websocket.Conn
 	Stopper             chan bool
 	stopAll             chan bool
 	logger              *zap.Logger
+	config              *Config
+	acks                *pendingAcks
+	outgoing            chan *queuedMessage
+	sendInterval        time.Duration
+	statsMu             sync.Mutex
+	stats               Stats
+	deliveries          *deliveries
+	directory           *Directory
+	// ctx is cancelled on Close, so pending web API calls are aborted.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu               sync.RWMutex
+	selfID           string
+	brain            *zha.Brain
+	socketConnection *websocket.Conn
+	server           *http.Server
+	closeOnce        sync.Once
 }
 
 // NewAdapter generates new Adapter
@@ -41,7 +97,7 @@ func NewAdapter(token string, opts ...Option) zha.Option {
 		for _, opt := range opts {
 			err := opt(&conf)
 			if err != nil {
-				return nil
+				return err
 			}
 		}
 
@@ -57,16 +113,39 @@ func NewAdapter(token string, opts ...Option) zha.Option {
 
 // NewSlackAdapter creates new Slack instnce
 func NewSlackAdapter(config *Config) *Adapter {
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultQueueSize
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
 	a := &Adapter{
-		WebAPIClient:     webapi.NewClient(config.Token),
+		WebAPIClient:     webapi.NewClient(config.Token, config.WebAPIOptions...),
 		RtmAPIClient:     rtmapi.NewClient(),
+		AppWebAPIClient:  webapi.NewClient(config.AppToken, config.WebAPIOptions...),
+		SocketModeClient: socketmode.NewClient(),
 		tryPing:          make(chan bool),
 		Events:           make(chan rtmapi.DecodedEvent, 100),
 		outgoingEventID:  rtmapi.NewOutgoingEventID(),
-		OutgoingMessages: make(chan *rtmapi.TextMessage, 100),
+		outgoing:         make(chan *queuedMessage, config.QueueSize),
+		sendInterval:     rtmSendInterval,
 		StartNewRtm:      make(chan bool),
 		Stopper:          make(chan bool),
 		stopAll:          make(chan bool),
+		logger:           config.Logger,
+		config:           config,
+		acks:             newPendingAcks(),
+		deliveries:       newDeliveries(deliveryTTL),
+		directory:        newDirectory(),
+		ctx:              ctx,
+		cancel:           cancel,
+	}
+
+	if config.SendTimeout <= 0 {
+		config.SendTimeout = defaultSendTimeout
+	}
+
+	if config.SocketMode || config.EventsAPI {
+		config.WebAPIMessages = true
 	}
 
 	if a.logger == nil {
@@ -78,6 +157,19 @@ func NewSlackAdapter(config *Config) *Adapter {
 
 // Register starts slacker
 func (s *Adapter) Register(b *zha.Brain) {
+	s.mu.Lock()
+	s.brain = b
+	s.mu.Unlock()
+
+	switch {
+	case s.config.SocketMode:
+		go s.runSocketMode(b)
+		return
+	case s.config.EventsAPI:
+		go s.runEventsAPI()
+		return
+	}
+
 	go s.supervise()
 	go s.sendEnqueuedMessage()
 	go s.receiveEvent(b)
@@ -121,14 +213,47 @@ func (s *Adapter) connect() error {
 	}
 
 	s.webSocketConnection = conn
+	s.directory.load(rtmInfo)
+
+	if rtmInfo.Self != nil {
+		s.mu.Lock()
+		s.selfID = rtmInfo.Self.ID
+		s.mu.Unlock()
+	}
+
 	return nil
 }
 
+// SelfID returns the user ID the bot is connected as.
+func (s *Adapter) SelfID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.selfID
+}
+
+// Directory returns the cache of the workspace's users and channels.
+func (s *Adapter) Directory() *Directory {
+	return s.directory
+}
+
+// Mention returns the slack representation of a user mention.
+func (s *Adapter) Mention(userID string) string {
+	return "<@" + userID + ">"
+}
+
+// isDirectChannel reports whether the channel ID is a direct message channel.
+func isDirectChannel(channelID string) bool {
+	return strings.HasPrefix(channelID, "D")
+}
+
 func (s *Adapter) disconnect() {
 	if s.webSocketConnection == nil {
 		return
 	}
 
+	s.acks.failAll(ErrNotConnected)
+
 	if err := s.webSocketConnection.Close(); err != nil {
 		s.logger.Error(
 			"error on connection close. type %T. value: %+v.",
@@ -150,7 +275,7 @@ func (s *Adapter) checkConnection() {
 func (s *Adapter) fetchRtmInfo() (*webapi.RtmStart, error) {
 	var rtmStart *webapi.RtmStart
 	err := retry.Interval(10, func() error {
-		r, e := s.WebAPIClient.RtmStart()
+		r, e := s.WebAPIClient.RtmStartContext(s.ctx)
 		rtmStart = r
 		return e
 	}, 500*time.Microsecond)
@@ -169,27 +294,6 @@ func (s *Adapter) connectRtm(rtm *webapi.RtmStart) (*websocket.Conn, error) {
 	return conn, err
 }
 
-func (s *Adapter) sendEnqueuedMessage() {
-	for {
-		select {
-		case <-s.stopAll:
-			return
-		case message := <-s.OutgoingMessages:
-			if s.webSocketConnection == nil {
-				continue
-			}
-
-			fmt.Printf("message: %#v", message)
-
-			event := rtmapi.NewOutgoingMessage(s.outgoingEventID, message)
-			if err := websocket.JSON.Send(s.webSocketConnection, event); err != nil {
-				fmt.Println(err, "eveneeeet")
-				s.logger.Error("failed to send event", zap.Any("event", event))
-			}
-		}
-	}
-}
-
 func (s *Adapter) receiveEvent(b *zha.Brain) {
 	for {
 		select {
@@ -214,11 +318,13 @@ func (s *Adapter) receiveEvent(b *zha.Brain) {
 
 			event, err := s.RtmAPIClient.DecodePayload(payload)
 			if err != nil {
-				switch err.(type) {
+				switch e := err.(type) {
 				case *rtmapi.EventTypeError:
 					s.logger.Warn("malformed payload was passed.", zap.Any("payload", payload))
 				case *rtmapi.ReplyStatusError:
-					s.logger.Error("something was wrong with previous posted message. %#v", zap.Any("error", err.Error()))
+					if !s.acks.resolve(e.Reply.ReplyTo, ack{err: e}) {
+						s.logger.Error("something was wrong with previous posted message. %#v", zap.Any("error", err.Error()))
+					}
 				default:
 					s.logger.Error("unhandled error occured on payload decode. %#v", zap.Any("error", err.Error()))
 				}
@@ -228,42 +334,332 @@ func (s *Adapter) receiveEvent(b *zha.Brain) {
 				continue
 			}
 
+			switch e := event.(type) {
+			case *rtmapi.WebSocketReply:
+				s.acks.resolve(e.ReplyTo, ack{reply: e})
+				continue
+			case *rtmapi.Goodbye:
+				s.logger.Info("slack is closing the connection, reconnecting")
+				s.StartNewRtm <- true
+				continue
+			case *rtmapi.ErrorEvent:
+				s.logger.Error("error event received", zap.Int("code", e.Error.Code), zap.String("msg", e.Error.Msg))
+				continue
+			}
+
 			s.logger.Debug("Received message", zap.Any("event", event))
 
-			if botInput, ok := event.(zha.BotInput); ok {
-				b.Emit(zha.ReciveMessageEvent{
-					Text:     botInput.GetMessage(),
-					ChannelD: botInput.GetRoomID(),
-				})
-			} else {
-				// s.logger.Warn("unhandeled")
-			}
+			s.emitEvent(b, event)
 		}
 	}
 }
 
+// emitEvent updates the directory and passes a decoded event on to the brain.
+func (s *Adapter) emitEvent(b *zha.Brain, event rtmapi.DecodedEvent) {
+	s.directory.apply(event)
+
+	switch e := event.(type) {
+	case *rtmapi.Hello, *rtmapi.Pong, *rtmapi.Goodbye, *rtmapi.ErrorEvent, *rtmapi.ReconnectURL,
+		*rtmapi.TeamMigrationStarted, *rtmapi.PresenceChange:
+		// connection and presence events are not passed on to handlers
+	case *rtmapi.ReactionAdded:
+		b.Emit(zha.ReactionAddedEvent{Reaction: s.reaction(&e.ReactionEvent, event)})
+	case *rtmapi.ReactionRemoved:
+		b.Emit(zha.ReactionRemovedEvent{Reaction: s.reaction(&e.ReactionEvent, event)})
+	case *rtmapi.MessageChanged:
+		if evt := s.messageChangedEvent(e); !s.ignored(evt.FromSelf, evt.FromBot) {
+			b.Emit(evt)
+		}
+	case *rtmapi.MessageDeleted:
+		if evt := s.messageDeletedEvent(e); !s.ignored(evt.FromSelf, evt.FromBot) {
+			b.Emit(evt)
+		}
+	case *rtmapi.ChannelJoin:
+		b.Emit(zha.ChannelJoinEvent{
+			ChannelID: e.Channel,
+			UserID:    e.User,
+			InviterID: e.Inviter,
+			FromSelf:  s.isSelf(e.User),
+			Raw:       event,
+		})
+	case zha.BotInput:
+		if evt := s.messageEvent(e, event); !s.ignored(evt.FromSelf, evt.FromBot) {
+			b.Emit(evt)
+		}
+	default:
+		// other events are emitted as they are decoded, e.g. handlers of
+		// rtmapi.MemberJoinedChannel receive member_joined_channel events
+		if value := reflect.ValueOf(event); value.Kind() == reflect.Ptr && !value.IsNil() && value.Elem().Kind() == reflect.Struct {
+			b.Emit(value.Elem().Interface())
+		}
+	}
+}
+
+// ignored reports whether messages of the bot itself or of other bots are
+// dropped by the configured policy.
+func (s *Adapter) ignored(fromSelf, fromBot bool) bool {
+	if fromSelf {
+		return !s.config.OwnMessages
+	}
+
+	return fromBot && !s.config.BotMessages
+}
+
+func (s *Adapter) isSelf(userID string) bool {
+	selfID := s.SelfID()
+	return selfID != "" && userID == selfID
+}
+
+func (s *Adapter) messageChangedEvent(changed *rtmapi.MessageChanged) zha.MessageChangedEvent {
+	message := &changed.Message
+	evt := zha.MessageChangedEvent{
+		ChannelID: changed.Channel,
+		ID:        message.GetMessageID(),
+		ThreadID:  message.GetThreadID(),
+		UserID:    message.User,
+		Text:      message.Text,
+		FromSelf:  s.isSelf(message.User),
+		FromBot:   fromBot(message),
+		Raw:       changed,
+	}
+
+	if changed.PreviousMessage != nil {
+		evt.PreviousText = changed.PreviousMessage.Text
+	}
+
+	return evt
+}
+
+func (s *Adapter) messageDeletedEvent(deleted *rtmapi.MessageDeleted) zha.MessageDeletedEvent {
+	evt := zha.MessageDeletedEvent{
+		ChannelID: deleted.Channel,
+		ID:        deleted.DeletedTimeStamp.String(),
+		Raw:       deleted,
+	}
+
+	if previous := deleted.PreviousMessage; previous != nil {
+		evt.UserID = previous.User
+		evt.PreviousText = previous.Text
+		evt.FromSelf = s.isSelf(previous.User)
+		evt.FromBot = fromBot(previous)
+	}
+
+	return evt
+}
+
+func (s *Adapter) reaction(event *rtmapi.ReactionEvent, raw interface{}) zha.Reaction {
+	return zha.Reaction{
+		Emoji:      event.Reaction,
+		ChannelID:  event.GetRoomID(),
+		MessageID:  event.GetMessageID(),
+		UserID:     event.User,
+		ItemUserID: event.ItemUser,
+		FromSelf:   s.isSelf(event.User),
+		Raw:        raw,
+	}
+}
+
+// threadedInput is implemented by inputs which know their message and thread IDs.
+type threadedInput interface {
+	GetMessageID() string
+	GetThreadID() string
+}
+
+// subtypedInput is implemented by inputs which know their subtype and bot.
+type subtypedInput interface {
+	GetSubType() string
+	GetBotID() string
+}
+
+func (s *Adapter) messageEvent(input zha.BotInput, raw interface{}) zha.ReciveMessageEvent {
+	evt := zha.ReciveMessageEvent{
+		Text:     input.GetMessage(),
+		ChannelD: input.GetRoomID(),
+		UserID:   input.GetSenderID(),
+		SentAt:   input.GetSentAt(),
+		FromSelf: s.isSelf(input.GetSenderID()),
+		Direct:   isDirectChannel(input.GetRoomID()),
+		Raw:      raw,
+	}
+
+	if threaded, ok := input.(threadedInput); ok {
+		evt.ID = threaded.GetMessageID()
+		evt.ThreadID = threaded.GetThreadID()
+	}
+
+	if subtyped, ok := input.(subtypedInput); ok {
+		evt.Subtype = subtyped.GetSubType()
+		evt.FromBot = fromBot(subtyped)
+	}
+
+	return evt
+}
+
+// fromBot reports whether the message was sent by a bot
+func fromBot(message subtypedInput) bool {
+	return message.GetBotID() != "" || message.GetSubType() == rtmapi.BOT_MESSAGE
+}
+
 // Close should shutdown the adapter
 func (s *Adapter) Close() error {
+	s.cancel()
+
+	if !s.config.SocketMode && !s.config.EventsAPI {
+		return nil
+	}
+
+	s.closeOnce.Do(func() {
+		close(s.stopAll)
+	})
+
+	s.mu.RLock()
+	conn := s.socketConnection
+	server := s.server
+	s.mu.RUnlock()
+
+	if server != nil {
+		if err := server.Close(); err != nil {
+			return err
+		}
+	}
+
+	if conn != nil {
+		return conn.Close()
+	}
+
 	return nil
 }
 
 // Send sends message to slack
 func (s *Adapter) Send(text, channelID string) error {
+	_, err := s.SendMessage(zha.OutgoingMessage{ChannelID: channelID, Text: text})
+	return err
+}
+
+// SendMessage sends message to slack, replies are sent into the thread of
+// the message with the timestamp in ThreadID.
+func (s *Adapter) SendMessage(msg zha.OutgoingMessage) (*zha.SentMessage, error) {
 	s.logger.Info("Sending message to channel",
-		zap.String("channel_id", channelID),
+		zap.String("channel_id", msg.ChannelID),
+		zap.String("thread_ts", msg.ThreadID),
 	)
+
+	// blocks can only be sent through the web API
+	if s.config.WebAPIMessages || len(msg.Blocks) > 0 {
+		return s.postMessage(msg)
+	}
+
 	if s.webSocketConnection == nil {
-		return nil
+		return nil, ErrNotConnected
+	}
+
+	// the messages ahead in the queue are sent one per send interval, so the
+	// timeout only starts once it is this message's turn
+	ahead := len(s.outgoing)
+
+	queued := newQueuedMessage(rtmapi.NewThreadTextMessage(msg.ChannelID, msg.ThreadID, msg.Text))
+	if !s.enqueue(queued) {
+		return nil, NewSendError(msg.ChannelID, ErrQueueFull)
+	}
+
+	timeout := s.config.SendTimeout + time.Duration(ahead)*s.sendInterval
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case result := <-queued.done:
+		if result.err != nil {
+			return nil, NewSendError(msg.ChannelID, result.err)
+		}
+
+		return &zha.SentMessage{ChannelID: msg.ChannelID, ID: result.reply.TimeStamp.String()}, nil
+	case <-timer.C:
+		if id, sent := queued.cancel(); sent {
+			s.acks.remove(id)
+		}
+
+		return nil, NewSendError(msg.ChannelID, ErrSendTimeout)
+	}
+}
+
+// EditMessage replaces the text of a message sent by the bot
+func (s *Adapter) EditMessage(sent zha.SentMessage, text string) error {
+	message := webapi.NewUpdateMessage(sent.ChannelID, sent.ID, text)
+	message.Parse = "none"
+
+	if _, err := s.WebAPIClient.ChatUpdateContext(s.ctx, message); err != nil {
+		s.logger.Error("failed to update message", zap.Error(err))
+		return NewSendError(sent.ChannelID, err)
 	}
 
-	message := rtmapi.NewTextMessage(channelID, text)
+	return nil
+}
 
-	fmt.Printf("messsageee: %#v", message)
+// DeleteMessage deletes a message sent by the bot
+func (s *Adapter) DeleteMessage(sent zha.SentMessage) error {
+	if _, err := s.WebAPIClient.ChatDeleteContext(s.ctx, sent.ChannelID, sent.ID); err != nil {
+		s.logger.Error("failed to delete message", zap.Error(err))
+		return NewSendError(sent.ChannelID, err)
+	}
+
+	return nil
+}
 
-	event := rtmapi.NewOutgoingMessage(s.outgoingEventID, message)
-	if err := websocket.JSON.Send(s.webSocketConnection, event); err != nil {
-		s.logger.Error("failed to send event", zap.Any("error", err.Error()))
+// React adds the emoji reaction to the message. Reacting twice with the same
+// emoji is not an error.
+func (s *Adapter) React(channelID, messageID, emoji string) error {
+	err := s.WebAPIClient.ReactionsAddContext(s.ctx, channelID, messageID, strings.Trim(emoji, ":"))
+	if err != nil && !errors.Is(err, webapi.ErrAlreadyReacted) {
+		s.logger.Error("failed to add reaction", zap.Error(err))
+		return NewSendError(channelID, err)
 	}
 
 	return nil
 }
+
+// SendFile uploads the file and shares it to the channel
+func (s *Adapter) SendFile(file zha.OutgoingFile) (*zha.SentMessage, error) {
+	upload := &webapi.FileUpload{
+		Filename:        file.Filename,
+		Reader:          file.Content,
+		Length:          file.Length,
+		Title:           file.Title,
+		InitialComment:  file.Comment,
+		Channels:        []string{file.ChannelID},
+		ThreadTimeStamp: file.ThreadID,
+	}
+	if file.Snippet {
+		upload.Filetype = "text"
+	}
+
+	if _, err := s.WebAPIClient.UploadFileContext(s.ctx, upload); err != nil {
+		s.logger.Error("failed to upload file", zap.Error(err))
+		return nil, NewSendError(file.ChannelID, err)
+	}
+
+	return &zha.SentMessage{ChannelID: file.ChannelID}, nil
+}
+
+func (s *Adapter) postMessage(msg zha.OutgoingMessage) (*zha.SentMessage, error) {
+	post := webapi.NewPostMessage(msg.ChannelID, msg.Text)
+	post.Parse = "none"
+	post.AsUser = true
+	post.ThreadTimeStamp = msg.ThreadID
+
+	for _, block := range msg.Blocks {
+		b, ok := block.(webapi.Block)
+		if !ok {
+			return nil, NewSendError(msg.ChannelID, fmt.Errorf("unsupported block %T", block))
+		}
+
+		post.Blocks = append(post.Blocks, b)
+	}
+
+	response, err := s.WebAPIClient.PostMessageContext(s.ctx, post)
+	if err != nil {
+		s.logger.Error("failed to post message", zap.Error(err))
+		return nil, NewSendError(msg.ChannelID, err)
+	}
+
+	return &zha.SentMessage{ChannelID: response.Channel, ID: response.TimeStamp}, nil
+}
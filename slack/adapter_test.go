@@ -0,0 +1,368 @@
+package slack
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"gitlab.com/kochevRisto/go-zha"
+	"gitlab.com/kochevRisto/go-zha/slack/rtmapi"
+	"gitlab.com/kochevRisto/go-zha/slack/webapi"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+// ackServer acknowledges RTM messages like slack does, it rejects messages
+// with the text "reject" and ignores messages with the text "ignore".
+func ackServer(ws *websocket.Conn) {
+	defer ws.Close()
+
+	for {
+		message := rtmapi.OutgoingMessage{}
+		if err := websocket.JSON.Receive(ws, &message); err != nil {
+			return
+		}
+
+		var reply string
+		switch message.Text {
+		case "ignore":
+			continue
+		case "reject":
+			reply = fmt.Sprintf(`{"ok": false, "reply_to": %d, "error": {"code": 2, "msg": "message text is missing"}}`, message.ID)
+		default:
+			reply = fmt.Sprintf(`{"ok": true, "reply_to": %d, "ts": "1355517523.00000%d", "text": %q}`, message.ID, message.ID, message.Text)
+		}
+
+		if err := websocket.Message.Send(ws, reply); err != nil {
+			return
+		}
+	}
+}
+
+func newConnectedAdapter(t *testing.T, handler websocket.Handler) (*Adapter, func()) {
+	server := httptest.NewServer(handler)
+
+	adapter := NewSlackAdapter(&Config{Token: "xoxb-test", SendTimeout: 200 * time.Millisecond})
+	conn, err := adapter.RtmAPIClient.Connect("ws://" + server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to connect %#v", err)
+	}
+	adapter.webSocketConnection = conn
+
+	go adapter.receiveEvent(zha.NewBrain(zap.NewNop(), time.Second))
+	go adapter.sendEnqueuedMessage()
+
+	return adapter, func() {
+		close(adapter.stopAll)
+		conn.Close()
+		server.Close()
+	}
+}
+
+func TestSendMessageAcknowledged(t *testing.T) {
+	adapter, stop := newConnectedAdapter(t, ackServer)
+	defer stop()
+
+	sent, err := adapter.SendMessage(zha.OutgoingMessage{ChannelID: "C1", Text: "hello"})
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if sent.ChannelID != "C1" || sent.ID != "1355517523.000001" {
+		t.Errorf("unexpected sent message %#v", sent)
+	}
+}
+
+func TestSendMessageRejected(t *testing.T) {
+	adapter, stop := newConnectedAdapter(t, ackServer)
+	defer stop()
+
+	_, err := adapter.SendMessage(zha.OutgoingMessage{ChannelID: "C1", Text: "reject"})
+	sendErr, ok := err.(*SendError)
+	if !ok {
+		t.Fatalf("expected send error, got %#v", err)
+	}
+
+	if _, ok := sendErr.Err.(*rtmapi.ReplyStatusError); !ok {
+		t.Errorf("expected reply status error, got %#v", sendErr.Err)
+	}
+}
+
+func TestSendMessageTimeout(t *testing.T) {
+	adapter, stop := newConnectedAdapter(t, ackServer)
+	defer stop()
+
+	_, err := adapter.SendMessage(zha.OutgoingMessage{ChannelID: "C1", Text: "ignore"})
+	if sendErr, ok := err.(*SendError); !ok || sendErr.Err != ErrSendTimeout {
+		t.Errorf("expected timeout error, got %#v", err)
+	}
+}
+
+func TestSendMessageTimeoutAlone(t *testing.T) {
+	adapter := NewSlackAdapter(&Config{Token: "xoxb-test", SendTimeout: 50 * time.Millisecond})
+	// nothing sends the queued message
+	adapter.webSocketConnection = &websocket.Conn{}
+	adapter.sendInterval = time.Second
+
+	// no message is ahead in the queue, so the send interval is not added
+	start := time.Now()
+	_, err := adapter.SendMessage(zha.OutgoingMessage{ChannelID: "C1", Text: "alone"})
+	if !errors.Is(err, ErrSendTimeout) {
+		t.Fatalf("expected timeout error, got %#v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("timeout of a message alone in the queue took %s", elapsed)
+	}
+}
+
+func TestSendMessageNotConnected(t *testing.T) {
+	adapter := NewSlackAdapter(&Config{Token: "xoxb-test"})
+
+	if _, err := adapter.SendMessage(zha.OutgoingMessage{ChannelID: "C1", Text: "hello"}); err != ErrNotConnected {
+		t.Errorf("expected not connected error, got %#v", err)
+	}
+}
+
+func TestSendMessageBlocks(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var blocks string
+	httpmock.RegisterResponder("POST", "https://slack.com/api/chat.postMessage", func(req *http.Request) (*http.Response, error) {
+		if err := req.ParseForm(); err != nil {
+			return nil, err
+		}
+		blocks = req.PostForm.Get("blocks")
+
+		return httpmock.NewJsonResponse(200, &webapi.PostMessageResponse{
+			APIResponse: webapi.APIResponse{OK: true},
+			Channel:     "C1",
+			TimeStamp:   "1.2",
+		})
+	})
+
+	// blocks are posted through the web API even though the adapter uses RTM
+	adapter := NewSlackAdapter(&Config{Token: "xoxb-test"})
+	sent, err := adapter.SendMessage(zha.OutgoingMessage{
+		ChannelID: "C1",
+		Text:      "fallback",
+		Blocks:    []interface{}{webapi.NewDividerBlock()},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if sent.ID != "1.2" || blocks != `[{"type":"divider"}]` {
+		t.Errorf("unexpected sent message %#v with blocks %q", sent, blocks)
+	}
+
+	_, err = adapter.SendMessage(zha.OutgoingMessage{ChannelID: "C1", Text: "fallback", Blocks: []interface{}{"divider"}})
+	if _, ok := err.(*SendError); !ok {
+		t.Errorf("expected send error for unsupported block, got %#v", err)
+	}
+}
+
+func TestSendMessageAPIError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "https://slack.com/api/chat.postMessage",
+		httpmock.NewStringResponder(200, `{"ok": false, "error": "not_in_channel"}`),
+	)
+
+	adapter := NewSlackAdapter(&Config{Token: "xoxb-test", WebAPIMessages: true})
+	_, err := adapter.SendMessage(zha.OutgoingMessage{ChannelID: "C1", Text: "hello"})
+	if !errors.Is(err, webapi.ErrNotInChannel) {
+		t.Errorf("expected not_in_channel error, got %#v", err)
+	}
+}
+
+func TestSendMessageInterval(t *testing.T) {
+	adapter, stop := newConnectedAdapter(t, ackServer)
+	defer stop()
+	adapter.sendInterval = 50 * time.Millisecond
+
+	start := time.Now()
+	for _, text := range []string{"first", "second"} {
+		if _, err := adapter.SendMessage(zha.OutgoingMessage{ChannelID: "C1", Text: text}); err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("messages were not spaced, took %s", elapsed)
+	}
+
+	if stats := adapter.Stats(); stats.Throttled != 1 || stats.QueueDepth != 0 || stats.QueueCapacity != defaultQueueSize {
+		t.Errorf("unexpected stats %#v", stats)
+	}
+}
+
+func TestSendMessageQueued(t *testing.T) {
+	adapter, stop := newConnectedAdapter(t, ackServer)
+	defer stop()
+	adapter.sendInterval = 30 * time.Millisecond
+
+	// sending all of them takes longer than the send timeout of 200ms
+	errs := make(chan error, 12)
+	for i := 0; i < cap(errs); i++ {
+		go func(i int) {
+			_, err := adapter.SendMessage(zha.OutgoingMessage{ChannelID: "C1", Text: fmt.Sprintf("message %d", i)})
+			errs <- err
+		}(i)
+	}
+
+	for i := 0; i < cap(errs); i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	}
+}
+
+func TestSendMessageQueueFull(t *testing.T) {
+	adapter := NewSlackAdapter(&Config{Token: "xoxb-test", QueueSize: 1, SendTimeout: 100 * time.Millisecond})
+	// nothing sends the queued messages
+	adapter.webSocketConnection = &websocket.Conn{}
+
+	queued := make(chan error, 1)
+	go func() {
+		_, err := adapter.SendMessage(zha.OutgoingMessage{ChannelID: "C1", Text: "queued"})
+		queued <- err
+	}()
+
+	for adapter.Stats().QueueDepth == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err := adapter.SendMessage(zha.OutgoingMessage{ChannelID: "C1", Text: "dropped"})
+	if !errors.Is(err, ErrQueueFull) {
+		t.Errorf("expected queue full error, got %#v", err)
+	}
+
+	if err := <-queued; !errors.Is(err, ErrSendTimeout) {
+		t.Errorf("expected timeout of the queued message, got %#v", err)
+	}
+
+	if stats := adapter.Stats(); stats.Dropped != 1 {
+		t.Errorf("unexpected stats %#v", stats)
+	}
+}
+
+func TestEditAndDeleteMessage(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var updated, deleted url.Values
+	httpmock.RegisterResponder("POST", "https://slack.com/api/chat.update", func(req *http.Request) (*http.Response, error) {
+		req.ParseForm()
+		updated = req.PostForm
+		return httpmock.NewStringResponse(200, `{"ok": true}`), nil
+	})
+	httpmock.RegisterResponder("POST", "https://slack.com/api/chat.delete", func(req *http.Request) (*http.Response, error) {
+		req.ParseForm()
+		deleted = req.PostForm
+		return httpmock.NewStringResponse(200, `{"ok": false, "error": "message_not_found"}`), nil
+	})
+
+	adapter := NewSlackAdapter(&Config{Token: "xoxb-test"})
+	sent := zha.SentMessage{ChannelID: "C1", ID: "1.2"}
+
+	if err := adapter.EditMessage(sent, "deployed"); err != nil {
+		t.Errorf("unexpected error %#v", err)
+	}
+	if updated.Get("ts") != "1.2" || updated.Get("text") != "deployed" {
+		t.Errorf("unexpected update %v", updated)
+	}
+
+	err := adapter.DeleteMessage(sent)
+	if _, ok := err.(*SendError); !ok || webapi.ErrorCode(err) != "message_not_found" {
+		t.Errorf("expected send error, got %#v", err)
+	}
+	if deleted.Get("channel") != "C1" {
+		t.Errorf("unexpected delete %v", deleted)
+	}
+}
+
+func TestSendFile(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var uploaded string
+	var completed url.Values
+	httpmock.RegisterResponder("POST", "https://slack.com/api/files.getUploadURLExternal", httpmock.NewStringResponder(200,
+		`{"ok": true, "upload_url": "https://files.slack.com/upload/v1/F1", "file_id": "F1"}`))
+	httpmock.RegisterResponder("POST", "https://files.slack.com/upload/v1/F1", func(req *http.Request) (*http.Response, error) {
+		read, _ := ioutil.ReadAll(req.Body)
+		uploaded = string(read)
+		return httpmock.NewStringResponse(200, "OK"), nil
+	})
+	httpmock.RegisterResponder("POST", "https://slack.com/api/files.completeUploadExternal", func(req *http.Request) (*http.Response, error) {
+		req.ParseForm()
+		completed = req.PostForm
+		return httpmock.NewStringResponse(200, `{"ok": true, "files": [{"id": "F1"}]}`), nil
+	})
+
+	adapter := NewSlackAdapter(&Config{Token: "xoxb-test"})
+	sent, err := adapter.SendFile(zha.OutgoingFile{
+		ChannelID: "C1",
+		ThreadID:  "1.2",
+		Filename:  "notes.txt",
+		Comment:   "notes",
+		Content:   strings.NewReader("release notes"),
+		Length:    13,
+		Snippet:   true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if sent.ChannelID != "C1" || uploaded != "release notes" {
+		t.Errorf("unexpected upload %#v %q", sent, uploaded)
+	}
+	if completed.Get("channels") != "C1" || completed.Get("thread_ts") != "1.2" || completed.Get("initial_comment") != "notes" {
+		t.Errorf("unexpected share %v", completed)
+	}
+}
+
+func TestReact(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var reactions []string
+	httpmock.RegisterResponder("POST", "https://slack.com/api/reactions.add", func(req *http.Request) (*http.Response, error) {
+		req.ParseForm()
+		reactions = append(reactions, req.PostForm.Get("name"))
+		if len(reactions) > 1 {
+			return httpmock.NewStringResponse(200, `{"ok": false, "error": "already_reacted"}`), nil
+		}
+
+		return httpmock.NewStringResponse(200, `{"ok": true}`), nil
+	})
+
+	adapter := NewSlackAdapter(&Config{Token: "xoxb-test"})
+	for i := 0; i < 2; i++ {
+		if err := adapter.React("C1", "1.2", ":white_check_mark:"); err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	}
+
+	if len(reactions) != 2 || reactions[0] != "white_check_mark" {
+		t.Errorf("unexpected reactions %v", reactions)
+	}
+}
+
+func TestNewAdapterInvalidWebAPIOption(t *testing.T) {
+	bot := zha.NewBot("zha", NewAdapter("xoxb-test", WithWebAPIOptions(webapi.WithBaseURL("slack"))))
+
+	if err := bot.Run(); err == nil {
+		t.Error("expected Run to fail because of the invalid web API option")
+	}
+}
@@ -0,0 +1,133 @@
+package slack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"gitlab.com/kochevRisto/go-zha"
+	"gitlab.com/kochevRisto/go-zha/slack/webapi"
+	"go.uber.org/zap"
+)
+
+// CommandsPath is where the adapter's HTTP server receives slash commands
+const CommandsPath = "/slack/commands"
+
+// SlashCommand is the payload slack sends when a slash command is invoked
+type SlashCommand struct {
+	Token        string `json:"token"`
+	TeamID       string `json:"team_id"`
+	TeamDomain   string `json:"team_domain"`
+	EnterpriseID string `json:"enterprise_id,omitempty"`
+	ChannelID    string `json:"channel_id"`
+	ChannelName  string `json:"channel_name"`
+	UserID       string `json:"user_id"`
+	UserName     string `json:"user_name"`
+	Command      string `json:"command"`
+	Text         string `json:"text"`
+	ResponseURL  string `json:"response_url"`
+	TriggerID    string `json:"trigger_id"`
+	APIAppID     string `json:"api_app_id"`
+}
+
+// NewSlashCommand creates new SlashCommand from the form values slack posts
+func NewSlashCommand(values url.Values) *SlashCommand {
+	return &SlashCommand{
+		Token:        values.Get("token"),
+		TeamID:       values.Get("team_id"),
+		TeamDomain:   values.Get("team_domain"),
+		EnterpriseID: values.Get("enterprise_id"),
+		ChannelID:    values.Get("channel_id"),
+		ChannelName:  values.Get("channel_name"),
+		UserID:       values.Get("user_id"),
+		UserName:     values.Get("user_name"),
+		Command:      values.Get("command"),
+		Text:         values.Get("text"),
+		ResponseURL:  values.Get("response_url"),
+		TriggerID:    values.Get("trigger_id"),
+		APIAppID:     values.Get("api_app_id"),
+	}
+}
+
+// CommandsHandler returns the handler for slash command requests. Commands
+// are acknowledged right away, within the 3 seconds slack waits, and emitted
+// to the brain as zha.CommandEvent. Handlers respond later via response_url.
+func (s *Adapter) CommandsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := readVerifiedBody(r, s.config.SigningSecret)
+		if err != nil {
+			s.logger.Warn("rejected command request", zap.Error(err))
+			http.Error(w, "invalid request", http.StatusUnauthorized)
+			return
+		}
+
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+
+		command := NewSlashCommand(values)
+		if command.Command == "" {
+			http.Error(w, "command is missing", http.StatusBadRequest)
+			return
+		}
+
+		if ack := s.commandAck(); ack != nil {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(ack)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+
+		s.handleCommand(command)
+	})
+}
+
+// commandAck returns the immediate response to a command, if configured.
+func (s *Adapter) commandAck() *webapi.ResponseMessage {
+	if s.config.CommandAck == "" {
+		return nil
+	}
+
+	return webapi.NewResponseMessage(s.config.CommandAck, false)
+}
+
+func (s *Adapter) handleCommand(command *SlashCommand) {
+	brain := s.registeredBrain()
+	if brain == nil {
+		s.logger.Warn("command received before the adapter was registered", zap.String("command", command.Command))
+		return
+	}
+
+	brain.Emit(zha.CommandEvent{
+		Name:        strings.ToLower(strings.TrimPrefix(command.Command, "/")),
+		Text:        command.Text,
+		UserID:      command.UserID,
+		ChannelD:    command.ChannelID,
+		ResponseURL: command.ResponseURL,
+		TriggerID:   command.TriggerID,
+		Raw:         command,
+	})
+}
+
+// RespondCommand posts a delayed response to the response_url of a command
+func (s *Adapter) RespondCommand(response zha.CommandResponse) error {
+	if response.ResponseURL == "" {
+		return NewSendError("", ErrMissingResponseURL)
+	}
+
+	message := webapi.NewResponseMessage(response.Text, response.InChannel)
+	if err := s.WebAPIClient.PostResponseContext(s.ctx, response.ResponseURL, message); err != nil {
+		s.logger.Error("failed to respond to command", zap.Error(err))
+		return NewSendError("", err)
+	}
+
+	return nil
+}
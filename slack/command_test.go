@@ -0,0 +1,106 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"gitlab.com/kochevRisto/go-zha"
+	"gitlab.com/kochevRisto/go-zha/slack/webapi"
+)
+
+func TestCommandsHandler(t *testing.T) {
+	adapter, brain := newEventsAdapter()
+	adapter.config.CommandAck = "working on it"
+
+	body := url.Values{
+		"command":      {"/Deploy"},
+		"text":         {"api production"},
+		"user_id":      {"U1"},
+		"channel_id":   {"C1"},
+		"response_url": {"https://hooks.slack.com/commands/T1/1/abc"},
+		"trigger_id":   {"123.456"},
+	}.Encode()
+
+	w := httptest.NewRecorder()
+	adapter.HTTPHandler().ServeHTTP(w, signedRequest(CommandsPath, body))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", w.Code)
+	}
+
+	ack := &webapi.ResponseMessage{}
+	if err := json.Unmarshal(w.Body.Bytes(), ack); err != nil {
+		t.Fatalf("unexpected ack %q", w.Body.String())
+	}
+	if ack.ResponseType != "ephemeral" || ack.Text != "working on it" {
+		t.Errorf("unexpected ack %#v", ack)
+	}
+
+	received := make(chan zha.CommandEvent, 1)
+	brain.RegisterHandler(func(evt zha.CommandEvent) {
+		received <- evt
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	brain.Process(ctx)
+
+	select {
+	case evt := <-received:
+		if evt.Name != "deploy" || evt.Text != "api production" || evt.ChannelD != "C1" || evt.TriggerID != "123.456" {
+			t.Errorf("unexpected command event %#v", evt)
+		}
+	default:
+		t.Fatal("command event was not emitted")
+	}
+}
+
+func TestCommandsHandlerRejectsUnsigned(t *testing.T) {
+	adapter, _ := newEventsAdapter()
+
+	r := httptest.NewRequest(http.MethodPost, CommandsPath, nil)
+	w := httptest.NewRecorder()
+	adapter.HTTPHandler().ServeHTTP(w, r)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected unauthorized, got %d", w.Code)
+	}
+}
+
+func TestRespondCommand(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	responseURL := "https://hooks.slack.com/commands/T1/1/abc"
+	responses := make(chan webapi.ResponseMessage, 1)
+	httpmock.RegisterResponder("POST", responseURL, func(req *http.Request) (*http.Response, error) {
+		message := webapi.ResponseMessage{}
+		if err := json.NewDecoder(req.Body).Decode(&message); err != nil {
+			return nil, err
+		}
+		responses <- message
+
+		return httpmock.NewStringResponse(200, "ok"), nil
+	})
+
+	adapter := NewSlackAdapter(&Config{Token: "xoxb-test"})
+	err := adapter.RespondCommand(zha.CommandResponse{ResponseURL: responseURL, Text: "deployed", InChannel: true})
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	message := <-responses
+	if message.ResponseType != "in_channel" || message.Text != "deployed" {
+		t.Errorf("unexpected response %#v", message)
+	}
+
+	if err := adapter.RespondCommand(zha.CommandResponse{Text: "lost"}); err == nil {
+		t.Error("expected error without response url")
+	}
+}
@@ -0,0 +1,347 @@
+package slack
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"gitlab.com/kochevRisto/go-zha"
+	"gitlab.com/kochevRisto/go-zha/slack/rtmapi"
+	"gitlab.com/kochevRisto/go-zha/slack/webapi"
+)
+
+// Channel is a public, private or direct message channel of the directory.
+type Channel struct {
+	ID   string
+	Name string
+	// UserID is the other user of a direct message channel.
+	UserID     string
+	IsPrivate  bool
+	IsIM       bool
+	IsArchived bool
+	// IsMember is whether the bot is a member of the channel.
+	IsMember bool
+	Members  []string
+	Topic    string
+	Purpose  string
+}
+
+// Directory caches the users and channels of the workspace. It is filled
+// from rtm.start when the RTM connection is established and kept current from
+// the received events, so in Socket Mode and with the Events API it only
+// knows what the events told it.
+type Directory struct {
+	mu           sync.RWMutex
+	team         *webapi.Team
+	users        map[string]webapi.User
+	userNames    map[string]string
+	channels     map[string]*Channel
+	channelNames map[string]string
+	ims          map[string]string
+}
+
+func newDirectory() *Directory {
+	d := &Directory{}
+	d.reset()
+
+	return d
+}
+
+func (d *Directory) reset() {
+	d.users = map[string]webapi.User{}
+	d.userNames = map[string]string{}
+	d.channels = map[string]*Channel{}
+	d.channelNames = map[string]string{}
+	d.ims = map[string]string{}
+}
+
+// DirectoryOf returns the directory of the bot's slack adapter.
+func DirectoryOf(bot *zha.Bot) (*Directory, bool) {
+	adapter, ok := bot.Adapter.(*Adapter)
+	if !ok {
+		return nil, false
+	}
+
+	return adapter.Directory(), true
+}
+
+// load replaces the content of the directory with the rtm.start response.
+func (d *Directory) load(rtm *webapi.RtmStart) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.reset()
+	d.team = rtm.Team
+
+	for _, user := range rtm.Users {
+		d.setUser(user)
+	}
+	for _, channel := range rtm.Channels {
+		d.setChannel(publicChannel(channel))
+	}
+	for _, group := range rtm.Groups {
+		d.setChannel(&Channel{
+			ID:         group.ID,
+			Name:       group.Name,
+			IsPrivate:  true,
+			IsArchived: group.IsArchived,
+			IsMember:   true,
+			Members:    group.Members,
+			Topic:      group.Topic.Value,
+			Purpose:    group.Purpose.Value,
+		})
+	}
+	for _, im := range rtm.IMs {
+		d.setChannel(&Channel{ID: im.ID, UserID: im.User, IsIM: true, IsMember: true})
+	}
+}
+
+// apply updates the directory from a received event.
+func (d *Directory) apply(event rtmapi.DecodedEvent) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch e := event.(type) {
+	case *rtmapi.UserChange:
+		d.setUser(e.User)
+	case *rtmapi.TeamJoin:
+		d.setUser(e.User)
+	case *rtmapi.ChannelCreated:
+		d.setChannel(publicChannel(e.Channel))
+	case *rtmapi.ChannelJoined:
+		channel := publicChannel(e.Channel)
+		channel.IsMember = true
+		d.setChannel(channel)
+	case *rtmapi.ChannelLeft:
+		if channel, ok := d.channels[e.Channel]; ok {
+			channel.IsMember = false
+		}
+	case *rtmapi.ChannelRename:
+		d.rename(e.Channel.ID, e.Channel.Name)
+	case *rtmapi.GroupRename:
+		d.rename(e.Channel.ID, e.Channel.Name)
+	case *rtmapi.ChannelDeleted:
+		d.deleteChannel(e.Channel)
+	case *rtmapi.ChannelArchive:
+		if channel, ok := d.channels[e.Channel]; ok {
+			channel.IsArchived = true
+		}
+	case *rtmapi.ChannelUnarchive:
+		if channel, ok := d.channels[e.Channel]; ok {
+			channel.IsArchived = false
+		}
+	case *rtmapi.IMCreated:
+		d.setChannel(&Channel{ID: e.Channel.ID, UserID: e.User, IsIM: true, IsMember: true})
+	case *rtmapi.MemberJoinedChannel:
+		if channel, ok := d.channels[e.Channel]; ok && !contains(channel.Members, e.User) {
+			channel.Members = append(channel.Members, e.User)
+		}
+	case *rtmapi.MemberLeftChannel:
+		if channel, ok := d.channels[e.Channel]; ok {
+			channel.Members = remove(channel.Members, e.User)
+		}
+	}
+}
+
+func (d *Directory) setUser(user webapi.User) {
+	if old, ok := d.users[user.ID]; ok && d.userNames[old.Name] == user.ID {
+		delete(d.userNames, old.Name)
+	}
+
+	d.users[user.ID] = user
+	d.userNames[user.Name] = user.ID
+}
+
+func (d *Directory) setChannel(channel *Channel) {
+	d.deleteChannel(channel.ID)
+
+	d.channels[channel.ID] = channel
+	if channel.Name != "" {
+		d.channelNames[channel.Name] = channel.ID
+	}
+	if channel.IsIM {
+		d.ims[channel.UserID] = channel.ID
+	}
+}
+
+func (d *Directory) rename(id, name string) {
+	channel, ok := d.channels[id]
+	if !ok {
+		d.setChannel(&Channel{ID: id, Name: name, IsPrivate: strings.HasPrefix(id, "G")})
+		return
+	}
+
+	if d.channelNames[channel.Name] == id {
+		delete(d.channelNames, channel.Name)
+	}
+
+	channel.Name = name
+	d.channelNames[name] = id
+}
+
+func (d *Directory) deleteChannel(id string) {
+	channel, ok := d.channels[id]
+	if !ok {
+		return
+	}
+
+	delete(d.channels, id)
+	if d.channelNames[channel.Name] == id {
+		delete(d.channelNames, channel.Name)
+	}
+	if channel.IsIM && d.ims[channel.UserID] == id {
+		delete(d.ims, channel.UserID)
+	}
+}
+
+// Team returns the team the bot is connected to, it is nil before rtm.start.
+func (d *Directory) Team() *webapi.Team {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.team
+}
+
+// User returns the user with the given ID.
+func (d *Directory) User(id string) (webapi.User, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	user, ok := d.users[id]
+	return user, ok
+}
+
+// UserByName returns the user with the given name, e.g. "@ana". Users are
+// matched by their user name first and then by their display name.
+func (d *Directory) UserByName(name string) (webapi.User, bool) {
+	name = strings.TrimPrefix(name, "@")
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if id, ok := d.userNames[name]; ok {
+		return d.users[id], true
+	}
+
+	for _, user := range d.users {
+		if user.Profile.DisplayName == name {
+			return user, true
+		}
+	}
+
+	return webapi.User{}, false
+}
+
+// DisplayName returns the name the user is shown with in slack, or the user
+// ID if the user is not known.
+func (d *Directory) DisplayName(id string) string {
+	user, ok := d.User(id)
+	switch {
+	case !ok:
+		return id
+	case user.Profile.DisplayName != "":
+		return user.Profile.DisplayName
+	case user.RealName != "":
+		return user.RealName
+	default:
+		return user.Name
+	}
+}
+
+// Users returns every known user, sorted by ID.
+func (d *Directory) Users() []webapi.User {
+	d.mu.RLock()
+	users := make([]webapi.User, 0, len(d.users))
+	for _, user := range d.users {
+		users = append(users, user)
+	}
+	d.mu.RUnlock()
+
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users
+}
+
+// Channel returns the channel with the given ID.
+func (d *Directory) Channel(id string) (Channel, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.channel(id)
+}
+
+// ChannelByName returns the channel with the given name, e.g. "#ops".
+func (d *Directory) ChannelByName(name string) (Channel, bool) {
+	name = strings.TrimPrefix(name, "#")
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.channel(d.channelNames[name])
+}
+
+// DirectChannel returns the direct message channel with the given user.
+func (d *Directory) DirectChannel(userID string) (Channel, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.channel(d.ims[userID])
+}
+
+// Channels returns every known channel, sorted by ID.
+func (d *Directory) Channels() []Channel {
+	d.mu.RLock()
+	channels := make([]Channel, 0, len(d.channels))
+	for id := range d.channels {
+		channel, _ := d.channel(id)
+		channels = append(channels, channel)
+	}
+	d.mu.RUnlock()
+
+	sort.Slice(channels, func(i, j int) bool { return channels[i].ID < channels[j].ID })
+	return channels
+}
+
+// channel returns a copy of the channel, so it can be used without the lock.
+func (d *Directory) channel(id string) (Channel, bool) {
+	channel, ok := d.channels[id]
+	if !ok {
+		return Channel{}, false
+	}
+
+	copied := *channel
+	copied.Members = append([]string(nil), channel.Members...)
+	return copied, true
+}
+
+func publicChannel(channel webapi.Channel) *Channel {
+	return &Channel{
+		ID:         channel.ID,
+		Name:       channel.Name,
+		IsArchived: channel.IsArchived,
+		IsMember:   channel.IsMember,
+		Members:    channel.Members,
+		Topic:      channel.Topic.Value,
+		Purpose:    channel.Purpose.Value,
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func remove(values []string, value string) []string {
+	kept := values[:0]
+	for _, v := range values {
+		if v != value {
+			kept = append(kept, v)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,127 @@
+package slack
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"gitlab.com/kochevRisto/go-zha"
+	"gitlab.com/kochevRisto/go-zha/slack/rtmapi"
+	"gitlab.com/kochevRisto/go-zha/slack/webapi"
+)
+
+func loadedDirectory() *Directory {
+	d := newDirectory()
+	d.load(&webapi.RtmStart{
+		Team: &webapi.Team{ID: "T1", Name: "zha"},
+		Users: []webapi.User{
+			{ID: "U1", Name: "ana", TZ: "Europe/Skopje", Profile: webapi.UserProfile{DisplayName: "Ana"}},
+			{ID: "U2", Name: "marko", RealName: "Marko Markovski"},
+		},
+		Channels: []webapi.Channel{{ID: "C1", Name: "general", IsMember: true, Members: []string{"U1"}}},
+		Groups:   []webapi.Group{{ID: "G1", Name: "ops"}},
+		IMs:      []webapi.IM{{ID: "D1", User: "U2"}},
+	})
+
+	return d
+}
+
+func TestDirectoryLookups(t *testing.T) {
+	d := loadedDirectory()
+
+	if d.Team().Name != "zha" {
+		t.Errorf("unexpected team %#v", d.Team())
+	}
+
+	if user, ok := d.User("U1"); !ok || user.TZ != "Europe/Skopje" {
+		t.Errorf("unexpected user %#v", user)
+	}
+	if user, ok := d.UserByName("@marko"); !ok || user.ID != "U2" {
+		t.Errorf("unexpected user %#v", user)
+	}
+	if user, ok := d.UserByName("Ana"); !ok || user.ID != "U1" {
+		t.Errorf("expected lookup by display name, got %#v", user)
+	}
+
+	if d.DisplayName("U1") != "Ana" || d.DisplayName("U2") != "Marko Markovski" || d.DisplayName("U3") != "U3" {
+		t.Errorf("unexpected display names %q %q %q", d.DisplayName("U1"), d.DisplayName("U2"), d.DisplayName("U3"))
+	}
+
+	if channel, ok := d.ChannelByName("#ops"); !ok || channel.ID != "G1" || !channel.IsPrivate {
+		t.Errorf("unexpected channel %#v", channel)
+	}
+	if channel, ok := d.DirectChannel("U2"); !ok || channel.ID != "D1" || !channel.IsIM {
+		t.Errorf("unexpected channel %#v", channel)
+	}
+
+	if len(d.Users()) != 2 || len(d.Channels()) != 3 {
+		t.Errorf("unexpected directory %#v %#v", d.Users(), d.Channels())
+	}
+}
+
+func TestDirectoryEvents(t *testing.T) {
+	d := loadedDirectory()
+
+	events := []rtmapi.DecodedEvent{
+		&rtmapi.UserChange{User: webapi.User{ID: "U1", Name: "ana.k"}},
+		&rtmapi.TeamJoin{User: webapi.User{ID: "U3", Name: "iva"}},
+		&rtmapi.ChannelRename{Channel: rtmapi.RenamedChannel{ID: "C1", Name: "announcements"}},
+		&rtmapi.ChannelCreated{Channel: webapi.Channel{ID: "C2", Name: "deploys"}},
+		&rtmapi.ChannelArchive{Channel: "C2"},
+		&rtmapi.ChannelDeleted{Channel: "G1"},
+		&rtmapi.MemberJoinedChannel{User: "U3", Channel: "C1"},
+		&rtmapi.MemberLeftChannel{User: "U1", Channel: "C1"},
+		&rtmapi.ChannelJoined{Channel: webapi.Channel{ID: "C3", Name: "random", Members: []string{"U1", "U0BOT"}}},
+		&rtmapi.ChannelLeft{Channel: "C1"},
+	}
+	for _, event := range events {
+		d.apply(event)
+	}
+
+	if _, ok := d.UserByName("ana"); ok {
+		t.Error("expected the old user name to be forgotten")
+	}
+	if user, ok := d.UserByName("ana.k"); !ok || user.ID != "U1" {
+		t.Errorf("unexpected user %#v", user)
+	}
+	if _, ok := d.User("U3"); !ok {
+		t.Error("expected the new user to be known")
+	}
+
+	if _, ok := d.ChannelByName("general"); ok {
+		t.Error("expected the old channel name to be forgotten")
+	}
+	channel, ok := d.ChannelByName("announcements")
+	if !ok || channel.IsMember || !reflect.DeepEqual(channel.Members, []string{"U3"}) {
+		t.Errorf("unexpected channel %#v", channel)
+	}
+
+	if channel, ok := d.Channel("C2"); !ok || !channel.IsArchived {
+		t.Errorf("unexpected channel %#v", channel)
+	}
+	if _, ok := d.ChannelByName("ops"); ok {
+		t.Error("expected the deleted channel to be forgotten")
+	}
+
+	channel, ok = d.ChannelByName("random")
+	if !ok || !channel.IsMember || !reflect.DeepEqual(channel.Members, []string{"U1", "U0BOT"}) {
+		t.Errorf("expected the joined channel to be known, got %#v", channel)
+	}
+}
+
+func TestAdapterDirectory(t *testing.T) {
+	adapter, _ := newEventsAdapter()
+
+	body := `{"type": "event_callback", "event_id": "Ev1", "event": {"type": "channel_created", "channel": {"id": "C1", "name": "ops", "created": 1360782804, "creator": "U1"}}}`
+	adapter.HTTPHandler().ServeHTTP(httptest.NewRecorder(), signedRequest(EventsPath, body))
+
+	bot := &zha.Bot{Adapter: adapter}
+	directory, ok := DirectoryOf(bot)
+	if !ok {
+		t.Fatal("expected the directory of the slack adapter")
+	}
+
+	if channel, ok := directory.ChannelByName("#ops"); !ok || channel.ID != "C1" {
+		t.Errorf("unexpected channel %#v", channel)
+	}
+}
@@ -0,0 +1,46 @@
+package slack
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotConnected is returned when a message is sent while the adapter is
+// not connected to slack.
+var ErrNotConnected = errors.New("not connected to slack")
+
+// ErrSendTimeout is returned when slack did not acknowledge a sent message in time.
+var ErrSendTimeout = errors.New("message was not acknowledged in time")
+
+// ErrQueueFull is returned when too many messages are waiting to be sent.
+var ErrQueueFull = errors.New("outgoing message queue is full")
+
+// ErrMissingResponseURL is returned when a command response has no response_url.
+var ErrMissingResponseURL = errors.New("response url is missing")
+
+// SendError is returned when slack did not accept a message.
+type SendError struct {
+	ChannelID string
+	Err       error
+}
+
+// Error returns the error string
+func (e *SendError) Error() string {
+	return fmt.Sprintf("failed to send message to channel %s: %s", e.ChannelID, e.Err)
+}
+
+// Cause returns the underlying error
+func (e *SendError) Cause() error {
+	return e.Err
+}
+
+// Unwrap returns the underlying error, e.g. to match a webapi.APIError
+// with errors.Is
+func (e *SendError) Unwrap() error {
+	return e.Err
+}
+
+// NewSendError creates new SendError
+func NewSendError(channelID string, err error) *SendError {
+	return &SendError{ChannelID: channelID, Err: err}
+}
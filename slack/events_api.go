@@ -0,0 +1,204 @@
+package slack
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"gitlab.com/kochevRisto/go-zha"
+	"gitlab.com/kochevRisto/go-zha/slack/eventsapi"
+	"gitlab.com/kochevRisto/go-zha/slack/retry"
+	"go.uber.org/zap"
+)
+
+const (
+	// EventsPath is where the adapter's HTTP server receives Events API requests
+	EventsPath = "/slack/events"
+
+	// slack retries a delivery up to three times within a few minutes
+	deliveryTTL = 10 * time.Minute
+)
+
+// runEventsAPI fetches the identity of the bot and serves the HTTP endpoints
+// if a listen address is configured.
+func (s *Adapter) runEventsAPI() {
+	err := retry.Interval(10, func() error {
+		auth, err := s.WebAPIClient.AuthTestContext(s.ctx)
+		if err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		s.selfID = auth.UserID
+		s.mu.Unlock()
+
+		return nil
+	}, 500*time.Millisecond)
+	if err != nil {
+		s.logger.Error("failed to fetch bot identity", zap.Error(err))
+	}
+
+	if s.config.ListenAddr == "" {
+		return
+	}
+
+	server := &http.Server{Addr: s.config.ListenAddr, Handler: s.HTTPHandler()}
+
+	s.mu.Lock()
+	s.server = server
+	s.mu.Unlock()
+
+	s.logger.Info("Serving slack requests", zap.String("addr", s.config.ListenAddr))
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		s.logger.Error("slack HTTP server failed", zap.Error(err))
+	}
+}
+
+// HTTPHandler returns a handler serving every HTTP endpoint of the adapter,
+// to be mounted on an existing server.
+func (s *Adapter) HTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(EventsPath, s.EventsHandler())
+	mux.Handle(CommandsPath, s.CommandsHandler())
+	mux.Handle(InteractionsPath, s.InteractionsHandler())
+
+	return mux
+}
+
+// EventsHandler returns the handler for Events API requests. It answers
+// url_verification requests and emits the wrapped events of event_callback
+// requests to the brain. Requests have to be signed with the signing secret.
+func (s *Adapter) EventsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := readVerifiedBody(r, s.config.SigningSecret)
+		if err != nil {
+			s.logger.Warn("rejected events request", zap.Error(err))
+			http.Error(w, "invalid request", http.StatusUnauthorized)
+			return
+		}
+
+		payload, err := eventsapi.DecodePayload(body)
+		if err != nil {
+			s.logger.Warn("malformed events payload was passed.", zap.Error(err))
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+
+		switch payload.Type {
+		case eventsapi.URLVerification:
+			verification, err := eventsapi.DecodeVerification(body)
+			if err != nil {
+				http.Error(w, "invalid payload", http.StatusBadRequest)
+				return
+			}
+
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(verification.Challenge))
+		case eventsapi.EventCallback:
+			callback, err := eventsapi.DecodeCallback(body)
+			if err != nil {
+				http.Error(w, "invalid payload", http.StatusBadRequest)
+				return
+			}
+
+			if !s.deliveries.reserve(callback.EventID) {
+				w.WriteHeader(http.StatusOK)
+				s.logger.Debug("ignoring retried event",
+					zap.String("event_id", callback.EventID),
+					zap.String("retry_num", r.Header.Get("X-Slack-Retry-Num")),
+				)
+				return
+			}
+
+			// slack retries the delivery if the event could not be handled yet
+			brain := s.registeredBrain()
+			if brain == nil {
+				s.deliveries.release(callback.EventID)
+				s.logger.Warn("event received before the adapter was registered", zap.String("event_id", callback.EventID))
+				http.Error(w, "not ready", http.StatusServiceUnavailable)
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+
+			s.handleCallback(brain, callback)
+		default:
+			s.logger.Debug("unhandled events payload", zap.String("type", string(payload.Type)))
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+}
+
+func (s *Adapter) handleCallback(brain *zha.Brain, callback *eventsapi.Callback) {
+	event, err := callback.DecodeEvent()
+	if err != nil {
+		s.logger.Debug("unhandled event", zap.Error(err))
+		return
+	}
+
+	s.emitEvent(brain, event)
+}
+
+func (s *Adapter) registeredBrain() *zha.Brain {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.brain
+}
+
+// deliveries remembers the IDs of handled events so retried deliveries
+// are only handled once.
+type deliveries struct {
+	mu     sync.Mutex
+	seenAt map[string]time.Time
+	ttl    time.Duration
+	pruned time.Time
+}
+
+func newDeliveries(ttl time.Duration) *deliveries {
+	return &deliveries{seenAt: map[string]time.Time{}, ttl: ttl, pruned: time.Now()}
+}
+
+// reserve reports whether the event with the given ID is delivered the
+// first time and reserves it, so concurrent retries are not handled twice.
+// Expired IDs are pruned at most once per ttl.
+func (d *deliveries) reserve(id string) bool {
+	if id == "" {
+		return true
+	}
+
+	now := time.Now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if now.Sub(d.pruned) > d.ttl {
+		for seenID, at := range d.seenAt {
+			if now.Sub(at) > d.ttl {
+				delete(d.seenAt, seenID)
+			}
+		}
+		d.pruned = now
+	}
+
+	if at, ok := d.seenAt[id]; ok && now.Sub(at) <= d.ttl {
+		return false
+	}
+
+	d.seenAt[id] = now
+	return true
+}
+
+// release forgets the reservation of an event which could not be handled,
+// so a later retry is handled.
+func (d *deliveries) release(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.seenAt, id)
+}
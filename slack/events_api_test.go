@@ -0,0 +1,414 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gitlab.com/kochevRisto/go-zha"
+	"gitlab.com/kochevRisto/go-zha/slack/rtmapi"
+	"go.uber.org/zap"
+)
+
+const testSigningSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+func newEventsAdapter() (*Adapter, *zha.Brain) {
+	adapter := NewSlackAdapter(&Config{Token: "xoxb-test", EventsAPI: true, SigningSecret: testSigningSecret})
+	brain := zha.NewBrain(zap.NewNop(), time.Second)
+
+	adapter.mu.Lock()
+	adapter.brain = brain
+	adapter.mu.Unlock()
+
+	return adapter, brain
+}
+
+func signedRequest(path, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+	for key, values := range signedHeader(testSigningSecret, []byte(body), time.Now()) {
+		r.Header[key] = values
+	}
+
+	return r
+}
+
+func TestEventsURLVerification(t *testing.T) {
+	adapter, _ := newEventsAdapter()
+
+	w := httptest.NewRecorder()
+	adapter.HTTPHandler().ServeHTTP(w, signedRequest(EventsPath, `{"type": "url_verification", "challenge": "abc123"}`))
+
+	if w.Code != http.StatusOK || w.Body.String() != "abc123" {
+		t.Errorf("unexpected response %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestEventsRejectsInvalidSignature(t *testing.T) {
+	adapter, _ := newEventsAdapter()
+
+	r := signedRequest(EventsPath, `{"type": "url_verification", "challenge": "abc123"}`)
+	r.Header.Set("X-Slack-Signature", "v0=0000")
+
+	w := httptest.NewRecorder()
+	adapter.HTTPHandler().ServeHTTP(w, r)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected unauthorized, got %d", w.Code)
+	}
+}
+
+func TestEventsCallbackDeduplicated(t *testing.T) {
+	adapter, brain := newEventsAdapter()
+
+	body := `{"type": "event_callback", "event_id": "Ev1", "event": {"type": "message", "channel": "C1", "user": "U1", "text": "hello", "ts": "1355517523.000005"}}`
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		adapter.HTTPHandler().ServeHTTP(w, signedRequest(EventsPath, body))
+		if w.Code != http.StatusOK {
+			t.Errorf("unexpected status %d", w.Code)
+		}
+	}
+
+	received := make(chan zha.ReciveMessageEvent, 2)
+	brain.RegisterHandler(func(evt zha.ReciveMessageEvent) {
+		received <- evt
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	brain.Process(ctx)
+
+	if len(received) != 1 {
+		t.Fatalf("expected one event, got %d", len(received))
+	}
+
+	evt := <-received
+	expected := zha.ReciveMessageEvent{Text: "hello", ChannelD: "C1", UserID: "U1", ID: "1355517523.000005"}
+	evt.SentAt, evt.Raw = time.Time{}, nil
+	if !reflect.DeepEqual(evt, expected) {
+		t.Errorf("unexpected event %#v", evt)
+	}
+}
+
+func TestEventsReactions(t *testing.T) {
+	adapter, brain := newEventsAdapter()
+
+	bodies := []string{
+		`{"type": "event_callback", "event_id": "Ev1", "event": {"type": "reaction_added", "user": "U1", "reaction": "white_check_mark", "item_user": "U2", "item": {"type": "message", "channel": "C1", "ts": "1.2"}, "event_ts": "1.3"}}`,
+		`{"type": "event_callback", "event_id": "Ev2", "event": {"type": "reaction_removed", "user": "U1", "reaction": "white_check_mark", "item": {"type": "message", "channel": "C1", "ts": "1.2"}, "event_ts": "1.4"}}`,
+	}
+	for _, body := range bodies {
+		adapter.HTTPHandler().ServeHTTP(httptest.NewRecorder(), signedRequest(EventsPath, body))
+	}
+
+	received := make(chan interface{}, 2)
+	brain.RegisterHandler(func(evt zha.ReactionAddedEvent) {
+		received <- evt
+	})
+	brain.RegisterHandler(func(evt zha.ReactionRemovedEvent) {
+		received <- evt
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	brain.Process(ctx)
+
+	if len(received) != 2 {
+		t.Fatalf("expected two events, got %d", len(received))
+	}
+
+	added, ok := (<-received).(zha.ReactionAddedEvent)
+	if !ok {
+		t.Fatal("expected the added reaction first")
+	}
+	added.Raw = nil
+	expected := zha.Reaction{Emoji: "white_check_mark", ChannelID: "C1", MessageID: "1.2", UserID: "U1", ItemUserID: "U2"}
+	if added.Reaction != expected {
+		t.Errorf("unexpected reaction %#v", added)
+	}
+
+	if removed, ok := (<-received).(zha.ReactionRemovedEvent); !ok || removed.MessageID != "1.2" {
+		t.Errorf("unexpected event %#v", removed)
+	}
+}
+
+func TestEventsOtherEvents(t *testing.T) {
+	adapter, brain := newEventsAdapter()
+
+	body := `{"type": "event_callback", "event_id": "Ev1", "event": {"type": "member_joined_channel", "user": "U1", "channel": "C1", "channel_type": "C", "team": "T1"}}`
+	adapter.HTTPHandler().ServeHTTP(httptest.NewRecorder(), signedRequest(EventsPath, body))
+
+	received := make(chan rtmapi.MemberJoinedChannel, 1)
+	brain.RegisterHandler(func(evt rtmapi.MemberJoinedChannel) {
+		received <- evt
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	brain.Process(ctx)
+
+	if len(received) != 1 {
+		t.Fatalf("expected one event, got %d", len(received))
+	}
+
+	if evt := <-received; evt.User != "U1" || evt.Channel != "C1" {
+		t.Errorf("unexpected event %#v", evt)
+	}
+}
+
+func TestEmitEventFiltersConnectionEvents(t *testing.T) {
+	adapter, brain := newEventsAdapter()
+
+	adapter.emitEvent(brain, &rtmapi.Pong{})
+	adapter.emitEvent(brain, &rtmapi.PresenceChange{User: "U1", Presence: "away"})
+	adapter.emitEvent(brain, &rtmapi.UserTyping{Channel: "C1", User: "U1"})
+
+	received := make(chan interface{}, 3)
+	brain.RegisterHandler(func(evt rtmapi.Pong) { received <- evt })
+	brain.RegisterHandler(func(evt rtmapi.PresenceChange) { received <- evt })
+	brain.RegisterHandler(func(evt rtmapi.UserTyping) { received <- evt })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	brain.Process(ctx)
+
+	if len(received) != 1 {
+		t.Fatalf("expected one event, got %d", len(received))
+	}
+
+	// typing events are handled by the worker of their channel
+	if evt, ok := (<-received).(zha.ChannelEvent); !ok || evt.GetRoomID() != "C1" {
+		t.Errorf("expected a channel event, got %#v", evt)
+	}
+}
+
+func TestEventsMessagePolicy(t *testing.T) {
+	messages := []string{
+		`{"type": "message", "channel": "C1", "user": "U1", "text": "from a user", "ts": "1.1"}`,
+		`{"type": "message", "channel": "C1", "user": "UBOT", "bot_id": "B0", "text": "from self", "ts": "1.2"}`,
+		`{"type": "message", "subtype": "bot_message", "channel": "C1", "bot_id": "B1", "text": "from a bot", "ts": "1.3"}`,
+		`{"type": "message", "subtype": "thread_broadcast", "channel": "C1", "user": "U1", "text": "broadcast", "ts": "1.5", "thread_ts": "1.1"}`,
+	}
+
+	for _, test := range []struct {
+		config   Config
+		expected []string
+	}{
+		{Config{}, []string{"broadcast", "from a user"}},
+		{Config{OwnMessages: true, BotMessages: true}, []string{"broadcast", "from a bot", "from a user", "from self"}},
+	} {
+		adapter, brain := newEventsAdapter()
+		adapter.config.OwnMessages, adapter.config.BotMessages = test.config.OwnMessages, test.config.BotMessages
+		adapter.selfID = "UBOT"
+
+		for i, message := range messages {
+			body := fmt.Sprintf(`{"type": "event_callback", "event_id": "Ev%d", "event": %s}`, i, message)
+			adapter.HTTPHandler().ServeHTTP(httptest.NewRecorder(), signedRequest(EventsPath, body))
+		}
+
+		received := make(chan zha.ReciveMessageEvent, len(messages))
+		brain.RegisterHandler(func(evt zha.ReciveMessageEvent) {
+			received <- evt
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		brain.Process(ctx)
+		cancel()
+		close(received)
+
+		var texts []string
+		for evt := range received {
+			texts = append(texts, evt.Text)
+			if evt.Text == "broadcast" && (evt.Subtype != "thread_broadcast" || evt.ThreadID != "1.1") {
+				t.Errorf("unexpected broadcast %#v", evt)
+			}
+			if evt.Text == "from a bot" && !evt.FromBot {
+				t.Errorf("expected bot message %#v", evt)
+			}
+		}
+
+		sort.Strings(texts)
+		if !reflect.DeepEqual(texts, test.expected) {
+			t.Errorf("expected %v, got %v", test.expected, texts)
+		}
+	}
+}
+
+func TestEventsMessageSubtypes(t *testing.T) {
+	adapter, brain := newEventsAdapter()
+
+	bodies := []string{
+		`{"type": "event_callback", "event_id": "Ev1", "event": {"type": "message", "subtype": "message_changed", "hidden": true, "channel": "C1", "ts": "1.3", "message": {"type": "message", "user": "U1", "text": "deploy api", "ts": "1.1"}, "previous_message": {"type": "message", "user": "U1", "text": "deploy ap", "ts": "1.1"}}}`,
+		`{"type": "event_callback", "event_id": "Ev2", "event": {"type": "message", "subtype": "message_deleted", "hidden": true, "channel": "C1", "ts": "1.4", "deleted_ts": "1.1", "previous_message": {"type": "message", "user": "U1", "text": "deploy api", "ts": "1.1"}}}`,
+		`{"type": "event_callback", "event_id": "Ev3", "event": {"type": "message", "subtype": "channel_join", "channel": "C1", "user": "U2", "inviter": "U1", "text": "<@U2> has joined the channel", "ts": "1.5"}}`,
+	}
+	for _, body := range bodies {
+		adapter.HTTPHandler().ServeHTTP(httptest.NewRecorder(), signedRequest(EventsPath, body))
+	}
+
+	var messages, changes, deletes, joins int
+	var changed zha.MessageChangedEvent
+	var deleted zha.MessageDeletedEvent
+	var joined zha.ChannelJoinEvent
+	brain.RegisterHandler(func(zha.ReciveMessageEvent) { messages++ })
+	brain.RegisterHandler(func(evt zha.MessageChangedEvent) { changes++; changed = evt })
+	brain.RegisterHandler(func(evt zha.MessageDeletedEvent) { deletes++; deleted = evt })
+	brain.RegisterHandler(func(evt zha.ChannelJoinEvent) { joins++; joined = evt })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	brain.Process(ctx)
+
+	if messages != 0 || changes != 1 || deletes != 1 || joins != 1 {
+		t.Fatalf("unexpected events %d messages, %d changes, %d deletes, %d joins", messages, changes, deletes, joins)
+	}
+
+	if changed.ID != "1.1" || changed.Text != "deploy api" || changed.PreviousText != "deploy ap" || changed.UserID != "U1" {
+		t.Errorf("unexpected change %#v", changed)
+	}
+	if deleted.ID != "1.1" || deleted.PreviousText != "deploy api" {
+		t.Errorf("unexpected delete %#v", deleted)
+	}
+	if joined.UserID != "U2" || joined.InviterID != "U1" || joined.ChannelID != "C1" {
+		t.Errorf("unexpected join %#v", joined)
+	}
+}
+
+func TestEventsSystemMessagesNotHeard(t *testing.T) {
+	bot, adapter := newInteractionBot()
+
+	var heard []string
+	bot.Hear(".*", func(msg zha.Message) error {
+		heard = append(heard, msg.Text)
+		return nil
+	})
+
+	var system []rtmapi.SystemMessage
+	bot.Brain.RegisterHandler(func(evt rtmapi.SystemMessage) {
+		system = append(system, evt)
+	})
+
+	bodies := []string{
+		`{"type": "event_callback", "event_id": "Ev1", "event": {"type": "message", "subtype": "channel_topic", "channel": "C1", "user": "U1", "text": "<@U1> set the channel topic: deploys", "topic": "deploys", "ts": "1.1"}}`,
+		`{"type": "event_callback", "event_id": "Ev2", "event": {"type": "message", "channel": "C1", "user": "U1", "text": "hello", "ts": "1.2"}}`,
+	}
+	for _, body := range bodies {
+		adapter.HTTPHandler().ServeHTTP(httptest.NewRecorder(), signedRequest(EventsPath, body))
+	}
+
+	processFor(bot, 100*time.Millisecond)
+
+	if !reflect.DeepEqual(heard, []string{"hello"}) {
+		t.Errorf("expected only the user's message to be heard, got %v", heard)
+	}
+	if len(system) != 1 || system[0].SubType != "channel_topic" || system[0].GetRoomID() != "C1" {
+		t.Errorf("expected the channel_topic message to be emitted separately, got %#v", system)
+	}
+}
+
+func TestDeliveries(t *testing.T) {
+	d := newDeliveries(time.Millisecond)
+
+	if !d.reserve("Ev1") || d.reserve("Ev1") {
+		t.Error("expected only the first delivery to be handled")
+	}
+
+	d.release("Ev1")
+	if !d.reserve("Ev1") {
+		t.Error("expected a released delivery to be handled again")
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	if !d.reserve("Ev2") {
+		t.Error("expected a new delivery to be handled")
+	}
+	if _, ok := d.seenAt["Ev1"]; ok {
+		t.Error("expected the expired delivery to be pruned")
+	}
+	if !d.reserve("Ev1") {
+		t.Error("expected delivery to be forgotten after the ttl")
+	}
+}
+
+func TestDeliveriesConcurrentRetries(t *testing.T) {
+	d := newDeliveries(time.Minute)
+
+	var handled int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if d.reserve("Ev1") {
+				atomic.AddInt32(&handled, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if handled != 1 {
+		t.Errorf("expected the event to be handled once, got %d", handled)
+	}
+}
+
+func TestEventsRetriedBeforeRegistration(t *testing.T) {
+	adapter := NewSlackAdapter(&Config{Token: "xoxb-test", EventsAPI: true, SigningSecret: testSigningSecret})
+
+	body := `{"type": "event_callback", "event_id": "Ev1", "event": {"type": "message", "channel": "C1", "user": "U1", "text": "hello", "ts": "1.1"}}`
+	w := httptest.NewRecorder()
+	adapter.HTTPHandler().ServeHTTP(w, signedRequest(EventsPath, body))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected the delivery to be refused, got %d", w.Code)
+	}
+
+	brain := zha.NewBrain(zap.NewNop(), time.Second)
+	adapter.mu.Lock()
+	adapter.brain = brain
+	adapter.mu.Unlock()
+
+	received := make(chan zha.ReciveMessageEvent, 1)
+	brain.RegisterHandler(func(evt zha.ReciveMessageEvent) {
+		received <- evt
+	})
+
+	// the retry of slack is handled
+	w = httptest.NewRecorder()
+	adapter.HTTPHandler().ServeHTTP(w, signedRequest(EventsPath, body))
+	if w.Code != http.StatusOK {
+		t.Errorf("unexpected status %d", w.Code)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	brain.Process(ctx)
+
+	if len(received) != 1 {
+		t.Errorf("expected the retried event, got %d", len(received))
+	}
+}
+
+func TestMessageSubtypesFromBot(t *testing.T) {
+	adapter, _ := newEventsAdapter()
+
+	changed := adapter.messageChangedEvent(&rtmapi.MessageChanged{
+		Message: rtmapi.Message{SubType: rtmapi.BOT_MESSAGE, Text: "deployed"},
+	})
+	if !changed.FromBot {
+		t.Errorf("expected an edited bot message %#v", changed)
+	}
+
+	deleted := adapter.messageDeletedEvent(&rtmapi.MessageDeleted{
+		PreviousMessage: &rtmapi.Message{BotID: "B1", Text: "deployed"},
+	})
+	if !deleted.FromBot {
+		t.Errorf("expected a deleted bot message %#v", deleted)
+	}
+}
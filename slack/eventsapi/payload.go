@@ -0,0 +1,90 @@
+package eventsapi
+
+import (
+	"encoding/json"
+
+	"gitlab.com/kochevRisto/go-zha/slack/rtmapi"
+)
+
+// PayloadType is the type of an Events API payload.
+type PayloadType string
+
+const (
+	// EventCallback payload wraps an event the app is subscribed to
+	EventCallback = "event_callback"
+	// URLVerification payload is sent when the request URL is configured
+	URLVerification = "url_verification"
+)
+
+// Payload has the fields shared by every Events API payload.
+type Payload struct {
+	Type PayloadType `json:"type"`
+}
+
+// DecodePayload parses the type of the given payload.
+func DecodePayload(input json.RawMessage) (*Payload, error) {
+	payload := &Payload{}
+	if err := json.Unmarshal(input, payload); err != nil {
+		return nil, rtmapi.NewPayloadError(err.Error())
+	}
+
+	if payload.Type == "" {
+		return nil, rtmapi.NewEventTypeError("type is not given" + string(input))
+	}
+
+	return payload, nil
+}
+
+// Verification is a url_verification payload. Slack expects the challenge
+// to be sent back.
+type Verification struct {
+	Type      PayloadType `json:"type"`
+	Token     string      `json:"token,omitempty"`
+	Challenge string      `json:"challenge"`
+}
+
+// DecodeVerification parses the given url_verification payload.
+func DecodeVerification(input json.RawMessage) (*Verification, error) {
+	verification := &Verification{}
+	if err := json.Unmarshal(input, verification); err != nil {
+		return nil, rtmapi.NewPayloadError(err.Error())
+	}
+
+	if verification.Type != URLVerification {
+		return nil, rtmapi.NewEventTypeError("unexpected payload type " + string(verification.Type))
+	}
+
+	return verification, nil
+}
+
+// Callback is an event_callback payload which wraps the event the app is
+// subscribed to.
+type Callback struct {
+	Type      PayloadType     `json:"type"`
+	Token     string          `json:"token,omitempty"`
+	TeamID    string          `json:"team_id"`
+	APIAppID  string          `json:"api_app_id"`
+	Event     json.RawMessage `json:"event"`
+	EventID   string          `json:"event_id"`
+	EventTime int64           `json:"event_time"`
+}
+
+// DecodeCallback parses the given event_callback payload.
+func DecodeCallback(input json.RawMessage) (*Callback, error) {
+	callback := &Callback{}
+	if err := json.Unmarshal(input, callback); err != nil {
+		return nil, rtmapi.NewPayloadError(err.Error())
+	}
+
+	if callback.Type != EventCallback {
+		return nil, rtmapi.NewEventTypeError("unexpected payload type " + string(callback.Type))
+	}
+
+	return callback, nil
+}
+
+// DecodeEvent decodes the wrapped event. The events share their format with
+// the RTM API, so they are decoded into the rtmapi event structures.
+func (c *Callback) DecodeEvent() (rtmapi.DecodedEvent, error) {
+	return rtmapi.DecodeEvent(c.Event)
+}
@@ -0,0 +1,60 @@
+package eventsapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gitlab.com/kochevRisto/go-zha/slack/rtmapi"
+)
+
+func TestDecodeCallback(t *testing.T) {
+	input := json.RawMessage(`{"type": "event_callback", "team_id": "T1", "event_id": "Ev1", "event_time": 1355517523, "event": {"type": "message", "channel": "C1", "user": "U1", "text": "hello", "ts": "1355517523.000005"}}`)
+
+	callback, err := DecodeCallback(input)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if callback.EventID != "Ev1" || callback.TeamID != "T1" {
+		t.Errorf("unexpected callback %#v", callback)
+	}
+
+	event, err := callback.DecodeEvent()
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	message, ok := event.(*rtmapi.Message)
+	if !ok {
+		t.Fatalf("expected message event, got %#v", event)
+	}
+
+	if message.Text != "hello" || message.Channel != "C1" {
+		t.Errorf("unexpected message %#v", message)
+	}
+}
+
+func TestDecodeCallbackWithOtherType(t *testing.T) {
+	_, err := DecodeCallback(json.RawMessage(`{"type": "url_verification", "challenge": "abc"}`))
+	if _, ok := err.(*rtmapi.EventTypeError); !ok {
+		t.Errorf("expected event type error, got %#v", err)
+	}
+}
+
+func TestDecodeVerification(t *testing.T) {
+	input := json.RawMessage(`{"type": "url_verification", "token": "abc", "challenge": "3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P"}`)
+
+	payload, err := DecodePayload(input)
+	if err != nil || payload.Type != URLVerification {
+		t.Fatalf("unexpected payload %#v, error %#v", payload, err)
+	}
+
+	verification, err := DecodeVerification(input)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if verification.Challenge != "3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P" {
+		t.Errorf("unexpected challenge %q", verification.Challenge)
+	}
+}
@@ -0,0 +1,443 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"gitlab.com/kochevRisto/go-zha"
+	"gitlab.com/kochevRisto/go-zha/slack/webapi"
+	"go.uber.org/zap"
+)
+
+// InteractionsPath is where the adapter's HTTP server receives interactions
+const InteractionsPath = "/slack/interactions"
+
+// Interaction types
+const (
+	BlockActions   = "block_actions"
+	ViewSubmission = "view_submission"
+	ViewClosed     = "view_closed"
+)
+
+// Response actions of a view submission
+const (
+	ResponseActionErrors = "errors"
+	ResponseActionUpdate = "update"
+	ResponseActionPush   = "push"
+	ResponseActionClear  = "clear"
+)
+
+// viewResponseTimeout is how long the acknowledgement of a view submission
+// waits for the handlers, slack gives up on it after 3 seconds.
+const viewResponseTimeout = 2500 * time.Millisecond
+
+// InteractionPayload is sent when a user interacts with a block of a message
+// or submits or closes a modal
+// ex. https://api.slack.com/reference/interaction-payloads
+type InteractionPayload struct {
+	Type        string               `json:"type"`
+	TriggerID   string               `json:"trigger_id"`
+	ResponseURL string               `json:"response_url,omitempty"`
+	APIAppID    string               `json:"api_app_id"`
+	User        InteractionUser      `json:"user"`
+	Team        InteractionTeam      `json:"team"`
+	Channel     *InteractionChannel  `json:"channel,omitempty"`
+	Container   InteractionContainer `json:"container"`
+	Message     json.RawMessage      `json:"message,omitempty"`
+	Actions     []BlockAction        `json:"actions,omitempty"`
+	View        *webapi.View         `json:"view,omitempty"`
+	IsCleared   bool                 `json:"is_cleared,omitempty"`
+}
+
+// InteractionUser is the user who interacted
+type InteractionUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	TeamID   string `json:"team_id"`
+}
+
+// InteractionTeam is the workspace the interaction happened in
+type InteractionTeam struct {
+	ID     string `json:"id"`
+	Domain string `json:"domain"`
+}
+
+// InteractionChannel is the channel the interaction happened in
+type InteractionChannel struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// InteractionContainer is the message or view containing the interactive element
+type InteractionContainer struct {
+	Type        string `json:"type"`
+	MessageTS   string `json:"message_ts,omitempty"`
+	ChannelID   string `json:"channel_id,omitempty"`
+	ViewID      string `json:"view_id,omitempty"`
+	IsEphemeral bool   `json:"is_ephemeral,omitempty"`
+}
+
+// BlockAction is a single action of a block_actions interaction
+type BlockAction struct {
+	ActionID        string                `json:"action_id"`
+	BlockID         string                `json:"block_id"`
+	Type            string                `json:"type"`
+	ActionTS        string                `json:"action_ts"`
+	Value           string                `json:"value,omitempty"`
+	SelectedOption  *webapi.OptionObject  `json:"selected_option,omitempty"`
+	SelectedOptions []webapi.OptionObject `json:"selected_options,omitempty"`
+	SelectedDate    string                `json:"selected_date,omitempty"`
+	SelectedUser    string                `json:"selected_user,omitempty"`
+	SelectedChannel string                `json:"selected_channel,omitempty"`
+}
+
+// DecodeInteraction decodes the JSON interaction payload
+func DecodeInteraction(payload []byte) (*InteractionPayload, error) {
+	interaction := &InteractionPayload{}
+	if err := json.Unmarshal(payload, interaction); err != nil {
+		return nil, err
+	}
+
+	if interaction.Type == "" {
+		return nil, errors.New("interaction type is missing")
+	}
+
+	return interaction, nil
+}
+
+// channelID returns the channel the interaction happened in, if any
+func (p *InteractionPayload) channelID() string {
+	if p == nil {
+		return ""
+	}
+
+	if p.Channel != nil {
+		return p.Channel.ID
+	}
+
+	return p.Container.ChannelID
+}
+
+// BlockActionEvent is emitted for every action of a block_actions interaction
+type BlockActionEvent struct {
+	Action  BlockAction
+	Payload *InteractionPayload
+
+	adapter *Adapter
+}
+
+// GetRoomID returns the channel the action happened in
+func (e BlockActionEvent) GetRoomID() string {
+	return e.Payload.channelID()
+}
+
+// ViewSubmissionEvent is emitted when a user submits a modal
+type ViewSubmissionEvent struct {
+	Payload *InteractionPayload
+
+	adapter  *Adapter
+	response *viewResponse
+}
+
+// ViewSubmissionResponse is the body a view submission is acknowledged with,
+// it keeps the modal open to show errors, updates or pushes a view or closes
+// all views.
+// ex. https://api.slack.com/surfaces/modals/using#responding_to_submissions
+type ViewSubmissionResponse struct {
+	ResponseAction string            `json:"response_action"`
+	Errors         map[string]string `json:"errors,omitempty"`
+	View           *webapi.View      `json:"view,omitempty"`
+}
+
+// viewResponse holds the response the handlers of a view submission set,
+// handled is closed once they ran.
+type viewResponse struct {
+	handled <-chan struct{}
+
+	mu       sync.Mutex
+	response *ViewSubmissionResponse
+}
+
+func (r *viewResponse) set(response *ViewSubmissionResponse) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.response = response
+}
+
+func (r *viewResponse) get() *ViewSubmissionResponse {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.response
+}
+
+// ViewClosedEvent is emitted when a user closes a modal which was opened
+// with notify_on_close
+type ViewClosedEvent struct {
+	Payload *InteractionPayload
+
+	adapter *Adapter
+}
+
+// Interaction is passed to interaction handlers
+type Interaction struct {
+	Context context.Context
+	Payload *InteractionPayload
+	// Action is the action which was taken, only set for block actions.
+	Action *BlockAction
+
+	adapter  *Adapter
+	response *viewResponse
+}
+
+// View returns the view of a modal interaction
+func (i *Interaction) View() *webapi.View {
+	return i.Payload.View
+}
+
+// Value returns the submitted value of an input in the view
+func (i *Interaction) Value(blockID, actionID string) (webapi.ActionState, bool) {
+	view := i.Payload.View
+	if view == nil || view.State == nil {
+		return webapi.ActionState{}, false
+	}
+
+	state, ok := view.State.Values[blockID][actionID]
+	return state, ok
+}
+
+// SetResponse sets the body the view submission is acknowledged with. It
+// has to be called before the handler returns, a response set after slack
+// stopped waiting for the acknowledgement is dropped.
+func (i *Interaction) SetResponse(response *ViewSubmissionResponse) error {
+	if i.response == nil {
+		return errors.New("only view submissions accept a response")
+	}
+
+	i.response.set(response)
+	return nil
+}
+
+// UpdateMessage replaces the message containing the interactive element
+func (i *Interaction) UpdateMessage(text string, blocks ...webapi.Block) error {
+	return i.respond(&webapi.ResponseMessage{Text: text, ReplaceOriginal: true, Blocks: blocks})
+}
+
+// DeleteMessage deletes the message containing the interactive element
+func (i *Interaction) DeleteMessage() error {
+	return i.respond(&webapi.ResponseMessage{DeleteOriginal: true})
+}
+
+// Respond sends a message only visible to the user who interacted
+func (i *Interaction) Respond(text string) error {
+	return i.respond(webapi.NewResponseMessage(text, false))
+}
+
+func (i *Interaction) respond(message *webapi.ResponseMessage) error {
+	if i.Payload.ResponseURL == "" {
+		return NewSendError(i.Payload.channelID(), ErrMissingResponseURL)
+	}
+
+	if err := i.adapter.WebAPIClient.PostResponseContext(i.context(), i.Payload.ResponseURL, message); err != nil {
+		return NewSendError(i.Payload.channelID(), err)
+	}
+
+	return nil
+}
+
+// OpenView opens a modal for the user who interacted. Slack only accepts
+// the trigger_id for 3 seconds after the interaction.
+func (i *Interaction) OpenView(view *webapi.View) (*webapi.View, error) {
+	response, err := i.adapter.WebAPIClient.ViewsOpenContext(i.context(), i.Payload.TriggerID, view)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.View, nil
+}
+
+// context returns the context of the handler, or of the adapter if the
+// interaction was not passed to a handler
+func (i *Interaction) context() context.Context {
+	if i.Context != nil {
+		return i.Context
+	}
+
+	return i.adapter.ctx
+}
+
+// OnAction registers a handler for block actions with the given action_id.
+// The given middleware runs only for matching actions.
+func OnAction(bot *zha.Bot, actionID string, fun func(Interaction) error, mws ...zha.Middleware) (*zha.Handle, error) {
+	return onInteraction(bot, "OnAction", actionID, fun, mws, func(handle interactionHandler) interface{} {
+		return func(ctx context.Context, evt BlockActionEvent) error {
+			if evt.Action.ActionID != actionID {
+				return nil
+			}
+
+			action := evt.Action
+			return handle(ctx, evt, Interaction{Payload: evt.Payload, Action: &action, adapter: evt.adapter})
+		}
+	})
+}
+
+// OnViewSubmission registers a handler for submissions of modals with the
+// given callback_id. The given middleware runs only for matching views.
+func OnViewSubmission(bot *zha.Bot, callbackID string, fun func(Interaction) error, mws ...zha.Middleware) (*zha.Handle, error) {
+	return onInteraction(bot, "OnViewSubmission", callbackID, fun, mws, func(handle interactionHandler) interface{} {
+		return func(ctx context.Context, evt ViewSubmissionEvent) error {
+			if !matchesView(evt.Payload, callbackID) {
+				return nil
+			}
+
+			return handle(ctx, evt, Interaction{Payload: evt.Payload, adapter: evt.adapter, response: evt.response})
+		}
+	})
+}
+
+// OnViewClosed registers a handler for closed modals with the given
+// callback_id. The given middleware runs only for matching views.
+func OnViewClosed(bot *zha.Bot, callbackID string, fun func(Interaction) error, mws ...zha.Middleware) (*zha.Handle, error) {
+	return onInteraction(bot, "OnViewClosed", callbackID, fun, mws, func(handle interactionHandler) interface{} {
+		return func(ctx context.Context, evt ViewClosedEvent) error {
+			if !matchesView(evt.Payload, callbackID) {
+				return nil
+			}
+
+			return handle(ctx, evt, Interaction{Payload: evt.Payload, adapter: evt.adapter})
+		}
+	})
+}
+
+// interactionHandler runs the handler of a matching interaction event
+type interactionHandler func(ctx context.Context, evt interface{}, interaction Interaction) error
+
+// onInteraction registers fun with its middleware for the interaction events
+// of the handler returned by newHandler, which only passes on events
+// matching id.
+func onInteraction(
+	bot *zha.Bot,
+	name, id string,
+	fun func(Interaction) error,
+	mws []zha.Middleware,
+	newHandler func(interactionHandler) interface{},
+) (*zha.Handle, error) {
+	if fun == nil {
+		err := &zha.RegistrationError{Handler: "slack." + name + ": " + id, Err: errors.New("interaction handler is nil")}
+		bot.Reject(err)
+		return nil, err
+	}
+
+	return bot.RegisterMatcher(newHandler(func(ctx context.Context, evt interface{}, interaction Interaction) error {
+		return bot.Brain.Chain(func(ctx context.Context, _ interface{}) error {
+			interaction.Context = ctx
+			return fun(interaction)
+		}, mws...)(ctx, evt)
+	}))
+}
+
+func matchesView(payload *InteractionPayload, callbackID string) bool {
+	return payload.View != nil && payload.View.CallbackID == callbackID
+}
+
+// InteractionsHandler returns the handler for interaction requests. Slack
+// posts the JSON payload as the payload form value. Interactions are
+// acknowledged right away, except view submissions, which are acknowledged
+// with the response set by the handlers once they ran. Without a response
+// the submitted modal is closed.
+func (s *Adapter) InteractionsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := readVerifiedBody(r, s.config.SigningSecret)
+		if err != nil {
+			s.logger.Warn("rejected interaction request", zap.Error(err))
+			http.Error(w, "invalid request", http.StatusUnauthorized)
+			return
+		}
+
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+
+		interaction, err := DecodeInteraction([]byte(values.Get("payload")))
+		if err != nil {
+			s.logger.Warn("malformed interaction payload was passed.", zap.Error(err))
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+
+		if interaction.Type != ViewSubmission {
+			w.WriteHeader(http.StatusOK)
+			s.handleInteraction(interaction)
+			return
+		}
+
+		if response := s.awaitResponse(r.Context(), s.handleInteraction(interaction)); response != nil {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(response)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+}
+
+// handleInteraction emits the events of the interaction. For view
+// submissions it returns the response the handlers set, for other
+// interactions it returns nil.
+func (s *Adapter) handleInteraction(interaction *InteractionPayload) *viewResponse {
+	brain := s.registeredBrain()
+	if brain == nil {
+		s.logger.Warn("interaction received before the adapter was registered", zap.String("type", interaction.Type))
+		return nil
+	}
+
+	switch interaction.Type {
+	case BlockActions:
+		for _, action := range interaction.Actions {
+			brain.Emit(BlockActionEvent{Action: action, Payload: interaction, adapter: s})
+		}
+	case ViewSubmission:
+		response := &viewResponse{}
+		response.handled = brain.EmitHandled(ViewSubmissionEvent{Payload: interaction, adapter: s, response: response})
+
+		return response
+	case ViewClosed:
+		brain.Emit(ViewClosedEvent{Payload: interaction, adapter: s})
+	default:
+		s.logger.Debug("unhandled interaction", zap.String("type", interaction.Type))
+	}
+
+	return nil
+}
+
+// awaitResponse waits for the response of a view submission. It returns nil
+// if the handlers did not set one or did not finish in time.
+func (s *Adapter) awaitResponse(ctx context.Context, pending *viewResponse) *ViewSubmissionResponse {
+	if pending == nil {
+		return nil
+	}
+
+	timer := time.NewTimer(viewResponseTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-pending.handled:
+		return pending.get()
+	case <-timer.C:
+		s.logger.Warn("view submission handlers did not finish in time, acknowledging without a response")
+	case <-ctx.Done():
+	}
+
+	return nil
+}
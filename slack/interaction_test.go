@@ -0,0 +1,300 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"gitlab.com/kochevRisto/go-zha"
+	"gitlab.com/kochevRisto/go-zha/slack/webapi"
+)
+
+const responseURL = "https://hooks.slack.com/actions/T1/1/abc"
+
+func newInteractionBot() (*zha.Bot, *Adapter) {
+	adapter, _ := newEventsAdapter()
+	bot := zha.NewBot("zha")
+	bot.Adapter = adapter
+
+	adapter.mu.Lock()
+	adapter.brain = bot.Brain
+	adapter.mu.Unlock()
+
+	return bot, adapter
+}
+
+func postInteraction(t *testing.T, adapter *Adapter, payload string) {
+	body := url.Values{"payload": {payload}}.Encode()
+
+	w := httptest.NewRecorder()
+	adapter.HTTPHandler().ServeHTTP(w, signedRequest(InteractionsPath, body))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", w.Code)
+	}
+}
+
+func processFor(bot *zha.Bot, d time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	bot.Brain.Process(ctx)
+}
+
+func TestBlockActionRouting(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	responses := make(chan webapi.ResponseMessage, 1)
+	httpmock.RegisterResponder("POST", responseURL, func(req *http.Request) (*http.Response, error) {
+		message := webapi.ResponseMessage{}
+		if err := json.NewDecoder(req.Body).Decode(&message); err != nil {
+			return nil, err
+		}
+		responses <- message
+
+		return httpmock.NewStringResponse(200, "ok"), nil
+	})
+
+	bot, adapter := newInteractionBot()
+
+	var approved, rejected []string
+	OnAction(bot, "approve", func(i Interaction) error {
+		approved = append(approved, i.Action.Value)
+		return i.UpdateMessage("approved by <@" + i.Payload.User.ID + ">")
+	})
+	OnAction(bot, "reject", func(i Interaction) error {
+		rejected = append(rejected, i.Action.Value)
+		return nil
+	})
+
+	postInteraction(t, adapter, `{
+		"type": "block_actions",
+		"trigger_id": "123.456",
+		"response_url": "`+responseURL+`",
+		"user": {"id": "U1", "username": "kochev"},
+		"channel": {"id": "C1", "name": "deploys"},
+		"container": {"type": "message", "message_ts": "1.2", "channel_id": "C1"},
+		"actions": [{"action_id": "approve", "block_id": "b1", "type": "button", "value": "release-1"}]
+	}`)
+	processFor(bot, 100*time.Millisecond)
+
+	if len(approved) != 1 || approved[0] != "release-1" {
+		t.Errorf("unexpected approved actions %v", approved)
+	}
+	if len(rejected) != 0 {
+		t.Errorf("unexpected rejected actions %v", rejected)
+	}
+
+	select {
+	case message := <-responses:
+		if !message.ReplaceOriginal || message.Text != "approved by <@U1>" {
+			t.Errorf("unexpected update %#v", message)
+		}
+	default:
+		t.Error("message was not updated")
+	}
+}
+
+func TestViewSubmissionRouting(t *testing.T) {
+	bot, adapter := newInteractionBot()
+
+	submitted := make(chan string, 1)
+	OnViewSubmission(bot, "deploy", func(i Interaction) error {
+		state, ok := i.Value("service", "name")
+		if !ok {
+			t.Error("submitted value is missing")
+		}
+		submitted <- state.Value
+		return nil
+	})
+	OnViewSubmission(bot, "other", func(i Interaction) error {
+		t.Error("handler of another view was called")
+		return nil
+	})
+
+	closed := make(chan bool, 1)
+	OnViewClosed(bot, "deploy", func(i Interaction) error {
+		closed <- i.Payload.IsCleared
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bot.Brain.Process(ctx)
+
+	postInteraction(t, adapter, `{
+		"type": "view_submission",
+		"user": {"id": "U1"},
+		"view": {
+			"id": "V1",
+			"type": "modal",
+			"callback_id": "deploy",
+			"blocks": [],
+			"state": {"values": {"service": {"name": {"type": "plain_text_input", "value": "api"}}}}
+		}
+	}`)
+	postInteraction(t, adapter, `{
+		"type": "view_closed",
+		"user": {"id": "U1"},
+		"is_cleared": true,
+		"view": {"id": "V1", "type": "modal", "callback_id": "deploy", "blocks": []}
+	}`)
+
+	select {
+	case value := <-submitted:
+		if value != "api" {
+			t.Errorf("unexpected submitted value %q", value)
+		}
+	case <-time.After(time.Second):
+		t.Error("view submission was not handled")
+	}
+
+	select {
+	case cleared := <-closed:
+		if !cleared {
+			t.Error("expected cleared view")
+		}
+	case <-time.After(time.Second):
+		t.Error("closed view was not handled")
+	}
+}
+
+func TestViewSubmissionResponse(t *testing.T) {
+	bot, adapter := newInteractionBot()
+
+	OnViewSubmission(bot, "deploy", func(i Interaction) error {
+		state, _ := i.Value("service", "name")
+		if state.Value == "api" {
+			return nil
+		}
+
+		return i.SetResponse(&ViewSubmissionResponse{
+			ResponseAction: ResponseActionErrors,
+			Errors:         map[string]string{"service": "unknown service " + state.Value},
+		})
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bot.Brain.Process(ctx)
+
+	submit := func(service string) *httptest.ResponseRecorder {
+		body := url.Values{"payload": {`{
+			"type": "view_submission",
+			"user": {"id": "U1"},
+			"view": {
+				"id": "V1",
+				"type": "modal",
+				"callback_id": "deploy",
+				"blocks": [],
+				"state": {"values": {"service": {"name": {"type": "plain_text_input", "value": "` + service + `"}}}}
+			}
+		}`}}.Encode()
+
+		w := httptest.NewRecorder()
+		adapter.HTTPHandler().ServeHTTP(w, signedRequest(InteractionsPath, body))
+		return w
+	}
+
+	w := submit("db")
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", w.Code)
+	}
+
+	response := ViewSubmissionResponse{}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("unexpected response body %q", w.Body.String())
+	}
+	if response.ResponseAction != ResponseActionErrors || response.Errors["service"] != "unknown service db" {
+		t.Errorf("unexpected response %#v", response)
+	}
+
+	w = submit("api")
+	if w.Code != http.StatusOK || w.Body.Len() != 0 {
+		t.Errorf("expected an empty acknowledgement, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestSetResponseOnlyForViewSubmissions(t *testing.T) {
+	interaction := Interaction{Payload: &InteractionPayload{Type: BlockActions}}
+
+	if err := interaction.SetResponse(&ViewSubmissionResponse{ResponseAction: ResponseActionClear}); err == nil {
+		t.Error("expected an error for a block action")
+	}
+}
+
+func TestInteractionOpenView(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "https://slack.com/api/views.open", func(req *http.Request) (*http.Response, error) {
+		if err := req.ParseForm(); err != nil {
+			return nil, err
+		}
+
+		if req.PostForm.Get("trigger_id") != "123.456" {
+			return httpmock.NewStringResponse(400, "trigger_id is missing"), nil
+		}
+
+		return httpmock.NewJsonResponse(200, &webapi.ViewResponse{
+			APIResponse: webapi.APIResponse{OK: true},
+			View:        &webapi.View{ID: "V1", Type: "modal", CallbackID: "deploy"},
+		})
+	})
+
+	adapter, _ := newEventsAdapter()
+	interaction := Interaction{
+		Payload: &InteractionPayload{Type: BlockActions, TriggerID: "123.456"},
+		adapter: adapter,
+	}
+
+	view, err := interaction.OpenView(webapi.NewModal("deploy", "Deploy"))
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if view.ID != "V1" {
+		t.Errorf("unexpected view %#v", view)
+	}
+}
+
+func TestInteractionsHandlerRejectsMalformed(t *testing.T) {
+	adapter, _ := newEventsAdapter()
+
+	body := url.Values{"payload": {"{}"}}.Encode()
+	w := httptest.NewRecorder()
+	adapter.HTTPHandler().ServeHTTP(w, signedRequest(InteractionsPath, body))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected bad request, got %d", w.Code)
+	}
+}
+
+func TestInteractionNilHandler(t *testing.T) {
+	bot, _ := newInteractionBot()
+
+	if _, err := OnAction(bot, "approve", nil); err == nil {
+		t.Error("expected an error for a nil handler")
+	}
+	OnViewSubmission(bot, "deploy", nil)
+	OnViewClosed(bot, "deploy", nil)
+
+	rejected := bot.RejectedRegistrations()
+	if len(rejected) != 3 {
+		t.Fatalf("expected three rejected registrations, got %#v", rejected)
+	}
+
+	if rejected[0].Error() != `failed to register handler "slack.OnAction: approve": interaction handler is nil` {
+		t.Errorf("unexpected error %q", rejected[0].Error())
+	}
+	for i, name := range []string{"slack.OnAction: approve", "slack.OnViewSubmission: deploy", "slack.OnViewClosed: deploy"} {
+		if rejected[i].Handler != name {
+			t.Errorf("expected rejected handler %q, got %q", name, rejected[i].Handler)
+		}
+	}
+}
@@ -1,6 +1,12 @@
 package slack
 
-import "go.uber.org/zap"
+import (
+	"errors"
+	"time"
+
+	"gitlab.com/kochevRisto/go-zha/slack/webapi"
+	"go.uber.org/zap"
+)
 
 // Option is Slack options
 type Option func(*Config) error
@@ -12,3 +18,98 @@ func WithLogger(logger *zap.Logger) Option {
 		return nil
 	}
 }
+
+// WithWebAPIMessages sends messages with chat.postMessage instead of the RTM websocket
+func WithWebAPIMessages() Option {
+	return func(conf *Config) error {
+		conf.WebAPIMessages = true
+		return nil
+	}
+}
+
+// WithSendTimeout sets how long to wait for slack to acknowledge a sent message
+func WithSendTimeout(timeout time.Duration) Option {
+	return func(conf *Config) error {
+		conf.SendTimeout = timeout
+		return nil
+	}
+}
+
+// WithQueueSize sets how many RTM messages may wait to be sent
+func WithQueueSize(size int) Option {
+	return func(conf *Config) error {
+		if size <= 0 {
+			return errors.New("queue size must be positive")
+		}
+
+		conf.QueueSize = size
+		return nil
+	}
+}
+
+// WithSocketMode receives events over Socket Mode instead of the RTM API.
+// Socket Mode connections are opened with the app-level token.
+func WithSocketMode(appToken string) Option {
+	return func(conf *Config) error {
+		conf.SocketMode = true
+		conf.AppToken = appToken
+		return nil
+	}
+}
+
+// WithEventsAPI receives events with HTTP requests from slack instead of the
+// RTM API. Requests are verified with the signing secret of the app.
+func WithEventsAPI(signingSecret string) Option {
+	return func(conf *Config) error {
+		conf.EventsAPI = true
+		conf.SigningSecret = signingSecret
+		return nil
+	}
+}
+
+// WithListenAddr makes the adapter serve its HTTP endpoints on the given address
+func WithListenAddr(addr string) Option {
+	return func(conf *Config) error {
+		conf.ListenAddr = addr
+		return nil
+	}
+}
+
+// WithCommandAck sets the ephemeral text slash commands are acknowledged with
+// right away, before their handlers respond
+func WithCommandAck(text string) Option {
+	return func(conf *Config) error {
+		conf.CommandAck = text
+		return nil
+	}
+}
+
+// WithWebAPIOptions configures the web API clients, e.g. to use a custom HTTP
+// client or to point them at a different slack API. An invalid option makes
+// NewAdapter fail.
+func WithWebAPIOptions(opts ...webapi.Option) Option {
+	return func(conf *Config) error {
+		if _, err := webapi.NewClientE(conf.Token, opts...); err != nil {
+			return err
+		}
+
+		conf.WebAPIOptions = append(conf.WebAPIOptions, opts...)
+		return nil
+	}
+}
+
+// WithOwnMessages passes the messages the bot sent itself on to the brain
+func WithOwnMessages() Option {
+	return func(conf *Config) error {
+		conf.OwnMessages = true
+		return nil
+	}
+}
+
+// WithBotMessages passes the messages of other bots on to the brain
+func WithBotMessages() Option {
+	return func(conf *Config) error {
+		conf.BotMessages = true
+		return nil
+	}
+}
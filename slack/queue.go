@@ -0,0 +1,142 @@
+package slack
+
+import (
+	"sync"
+	"time"
+
+	"gitlab.com/kochevRisto/go-zha/slack/rtmapi"
+	"gitlab.com/kochevRisto/go-zha/slack/webapi"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	defaultQueueSize = 100
+
+	// slack accepts about one RTM message per second
+	rtmSendInterval = time.Second
+)
+
+// queuedMessage is a RTM message waiting to be sent
+type queuedMessage struct {
+	message *rtmapi.TextMessage
+	done    chan ack
+
+	mu        sync.Mutex
+	eventID   uint
+	sent      bool
+	cancelled bool
+}
+
+func newQueuedMessage(message *rtmapi.TextMessage) *queuedMessage {
+	return &queuedMessage{message: message, done: make(chan ack, 1)}
+}
+
+// cancel stops the message from being sent, it returns the event ID of the
+// message if it was already sent.
+func (q *queuedMessage) cancel() (uint, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.cancelled = true
+	return q.eventID, q.sent
+}
+
+// Stats describes the outgoing messages of the adapter
+type Stats struct {
+	// QueueDepth is the number of RTM messages waiting to be sent.
+	QueueDepth    int
+	QueueCapacity int
+	// Dropped is the number of messages rejected because the queue was full.
+	Dropped int64
+	// Throttled is the number of RTM messages delayed to keep the send interval.
+	Throttled int64
+	// WebAPI are the stats of the web API client.
+	WebAPI webapi.Stats
+}
+
+// Stats returns the stats of the outgoing messages
+func (s *Adapter) Stats() Stats {
+	s.statsMu.Lock()
+	stats := s.stats
+	s.statsMu.Unlock()
+
+	stats.QueueDepth = len(s.outgoing)
+	stats.QueueCapacity = cap(s.outgoing)
+	stats.WebAPI = s.WebAPIClient.Stats()
+
+	return stats
+}
+
+// enqueue adds the message to the outgoing queue, it fails if the queue is full.
+func (s *Adapter) enqueue(q *queuedMessage) bool {
+	select {
+	case s.outgoing <- q:
+		return true
+	default:
+		s.statsMu.Lock()
+		s.stats.Dropped++
+		s.statsMu.Unlock()
+
+		return false
+	}
+}
+
+// sendEnqueuedMessage sends the queued messages over the RTM websocket, at
+// most one per send interval.
+func (s *Adapter) sendEnqueuedMessage() {
+	var last time.Time
+	for {
+		select {
+		case <-s.stopAll:
+			return
+		case q := <-s.outgoing:
+			if wait := time.Until(last.Add(s.sendInterval)); wait > 0 {
+				s.statsMu.Lock()
+				s.stats.Throttled++
+				s.statsMu.Unlock()
+
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-s.stopAll:
+					timer.Stop()
+					return
+				}
+			}
+
+			if s.sendQueued(q) {
+				last = time.Now()
+			}
+		}
+	}
+}
+
+// sendQueued writes the message to the websocket, its ack is delivered by
+// receiveEvent. It returns false if the message was not written.
+func (s *Adapter) sendQueued(q *queuedMessage) bool {
+	q.mu.Lock()
+	if q.cancelled {
+		q.mu.Unlock()
+		return false
+	}
+
+	event := rtmapi.NewOutgoingMessage(s.outgoingEventID, q.message)
+	q.eventID = event.ID
+	q.sent = true
+	s.acks.register(event.ID, q.done)
+	q.mu.Unlock()
+
+	conn := s.webSocketConnection
+	if conn == nil {
+		s.acks.resolve(event.ID, ack{err: ErrNotConnected})
+		return false
+	}
+
+	if err := websocket.JSON.Send(conn, event); err != nil {
+		s.logger.Error("failed to send event", zap.Error(err))
+		s.acks.resolve(event.ID, ack{err: err})
+	}
+
+	return true
+}
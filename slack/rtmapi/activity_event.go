@@ -0,0 +1,69 @@
+package rtmapi
+
+import "gitlab.com/kochevRisto/go-zha/slack/webapi"
+
+// UserTyping is sent when a user is typing a message in a channel.
+type UserTyping struct {
+	CommonEvent
+	Channel string `json:"channel"`
+	User    string `json:"user"`
+}
+
+// GetRoomID returns the channel the user is typing in.
+func (typing UserTyping) GetRoomID() string {
+	return typing.Channel
+}
+
+// PresenceChange is sent when the presence of a user changes. Batched
+// presence changes list the users in Users.
+type PresenceChange struct {
+	CommonEvent
+	User     string   `json:"user,omitempty"`
+	Users    []string `json:"users,omitempty"`
+	Presence string   `json:"presence"`
+}
+
+// PinnedItem is the message or file of a pin event.
+type PinnedItem struct {
+	Type      string          `json:"type"`
+	Channel   string          `json:"channel"`
+	Message   *webapi.Message `json:"message,omitempty"`
+	File      *webapi.File    `json:"file,omitempty"`
+	Created   int64           `json:"created"`
+	CreatedBy string          `json:"created_by"`
+}
+
+// PinEvent holds the fields of pin_added and pin_removed events.
+type PinEvent struct {
+	CommonEvent
+	User           string     `json:"user"`
+	ChannelID      string     `json:"channel_id"`
+	Item           PinnedItem `json:"item"`
+	HasPins        bool       `json:"has_pins,omitempty"`
+	EventTimeStamp TimeStamp  `json:"event_ts"`
+}
+
+// GetRoomID returns the channel of the pinned item.
+func (pin PinEvent) GetRoomID() string {
+	return pin.ChannelID
+}
+
+// PinAdded is sent when an item is pinned to a channel.
+type PinAdded struct {
+	PinEvent
+}
+
+// PinRemoved is sent when an item is unpinned from a channel.
+type PinRemoved struct {
+	PinEvent
+}
+
+// FileShared is sent when a file is shared.
+type FileShared struct {
+	CommonEvent
+	FileID         string      `json:"file_id"`
+	File           webapi.File `json:"file"`
+	UserID         string      `json:"user_id"`
+	ChannelID      string      `json:"channel_id,omitempty"`
+	EventTimeStamp TimeStamp   `json:"event_ts"`
+}
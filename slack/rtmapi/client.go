@@ -28,6 +28,8 @@ func (c *Client) DecodePayload(payload json.RawMessage) (DecodedEvent, error) {
 }
 
 // DefaultPayloadDecoder decodes given paylaods, which includes various kinds of events.
+// Replies to client messages are returned as *WebSocketReply, or as
+// *ReplyStatusError if slack rejected the message.
 func DefaultPayloadDecoder(payload json.RawMessage) (DecodedEvent, error) {
 	decodedEvent, eventDecodedError := DecodeEvent(payload)
 
@@ -42,7 +44,7 @@ func DefaultPayloadDecoder(payload json.RawMessage) (DecodedEvent, error) {
 			return nil, NewReplyStatusError(reply)
 		}
 
-		return nil, nil
+		return reply, nil
 	}
 
 	if eventDecodedError != nil {
@@ -70,11 +72,18 @@ func ReceivePayload(conn *websocket.Conn) (json.RawMessage, error) {
 
 // TextMessage struct
 type TextMessage struct {
-	channel string
-	text    string
+	channel  string
+	text     string
+	threadTS string
 }
 
 // NewTextMessage creates new TextMessage instance
 func NewTextMessage(channel, text string) *TextMessage {
 	return &TextMessage{channel: channel, text: text}
 }
+
+// NewThreadTextMessage creates new TextMessage instance which is sent as a
+// reply in the thread of the message with the given timestamp
+func NewThreadTextMessage(channel, threadTS, text string) *TextMessage {
+	return &TextMessage{channel: channel, text: text, threadTS: threadTS}
+}
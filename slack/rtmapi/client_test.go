@@ -85,8 +85,13 @@ func TestDecodeReplyPayload(t *testing.T) {
 		t.Errorf("got error %#v", err)
 	}
 
-	if event != nil {
-		t.Errorf("expected nil event but got %#v", event)
+	reply, ok := event.(*WebSocketReply)
+	if !ok {
+		t.Fatalf("expected reply event but got %#v", event)
+	}
+
+	if reply.ReplyTo != 1 || reply.TimeStamp.String() != "1355517523.000005" {
+		t.Errorf("unexpected reply %#v", reply)
 	}
 }
 
@@ -0,0 +1,19 @@
+package rtmapi
+
+// Goodbye is sent when slack is about to close the connection, the client
+// should reconnect.
+type Goodbye struct {
+	CommonEvent
+}
+
+// ErrorEvent is sent when slack runs into an error on the connection.
+type ErrorEvent struct {
+	CommonEvent
+	Error ReplyError `json:"error"`
+}
+
+// ReconnectURL is sent with the URL a client may reconnect to.
+type ReconnectURL struct {
+	CommonEvent
+	URL string `json:"url"`
+}
@@ -54,6 +54,10 @@ type ReplyStatusError struct {
 
 // Error returns its error string
 func (e *ReplyStatusError) Error() string {
+	if e.Reply.Error != nil {
+		return fmt.Sprintf("error on previous message posting %d: %d %s", e.Reply.ReplyTo, e.Reply.Error.Code, e.Reply.Error.Msg)
+	}
+
 	return fmt.Sprintf("error on previous message posting %#v", e.Reply)
 }
 
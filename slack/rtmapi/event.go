@@ -16,6 +16,52 @@ const (
 	PING = "ping"
 	// PONG event type
 	PONG = "pong"
+	// GOODBYE is sent when slack is about to close the connection
+	GOODBYE = "goodbye"
+	// ERROR event type
+	ERROR = "error"
+	// RECONNECT_URL event type
+	RECONNECT_URL = "reconnect_url"
+	// USER_TYPING event type
+	USER_TYPING = "user_typing"
+	// PRESENCE_CHANGE event type
+	PRESENCE_CHANGE = "presence_change"
+	// PIN_ADDED event type
+	PIN_ADDED = "pin_added"
+	// PIN_REMOVED event type
+	PIN_REMOVED = "pin_removed"
+	// FILE_SHARED event type
+	FILE_SHARED = "file_shared"
+	// CHANNEL_JOINED event type
+	CHANNEL_JOINED = "channel_joined"
+	// CHANNEL_LEFT event type
+	CHANNEL_LEFT = "channel_left"
+	// REACTION_ADDED event type
+	REACTION_ADDED = "reaction_added"
+	// REACTION_REMOVED event type
+	REACTION_REMOVED = "reaction_removed"
+	// USER_CHANGE event type
+	USER_CHANGE = "user_change"
+	// TEAM_JOIN event type
+	TEAM_JOIN = "team_join"
+	// CHANNEL_CREATED event type
+	CHANNEL_CREATED = "channel_created"
+	// CHANNEL_RENAME event type
+	CHANNEL_RENAME = "channel_rename"
+	// CHANNEL_DELETED event type
+	CHANNEL_DELETED = "channel_deleted"
+	// CHANNEL_ARCHIVE event type
+	CHANNEL_ARCHIVE = "channel_archive"
+	// CHANNEL_UNARCHIVE event type
+	CHANNEL_UNARCHIVE = "channel_unarchive"
+	// GROUP_RENAME event type
+	GROUP_RENAME = "group_rename"
+	// IM_CREATED event type
+	IM_CREATED = "im_created"
+	// MEMBER_JOINED_CHANNEL event type
+	MEMBER_JOINED_CHANNEL = "member_joined_channel"
+	// MEMBER_LEFT_CHANNEL event type
+	MEMBER_LEFT_CHANNEL = "member_left_channel"
 )
 
 // CommonEvent have common fields on incoming/outgoing events
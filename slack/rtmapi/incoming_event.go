@@ -2,7 +2,6 @@ package rtmapi
 
 import (
 	"encoding/json"
-	"errors"
 	"time"
 )
 
@@ -20,8 +19,18 @@ type IncomingChannelEvent struct {
 // Message is message event on RTM
 type Message struct {
 	IncomingChannelEvent
+	SubType         string       `json:"subtype,omitempty"`
+	User            string       `json:"user"`
+	BotID           string       `json:"bot_id,omitempty"`
+	Text            string       `json:"text"`
+	TimeStamp       TimeStamp    `json:"ts"`
+	ThreadTimeStamp *TimeStamp   `json:"thread_ts,omitempty"`
+	Edited          *MessageEdit `json:"edited,omitempty"`
+}
+
+// MessageEdit tells who edited a message and when.
+type MessageEdit struct {
 	User      string    `json:"user"`
-	Text      string    `json:"text"`
 	TimeStamp TimeStamp `json:"ts"`
 }
 
@@ -45,6 +54,71 @@ func (message *Message) GetRoomID() string {
 	return message.Channel
 }
 
+// GetMessageID returns the message timestamp which identifies the message in its channel.
+func (message *Message) GetMessageID() string {
+	return message.TimeStamp.String()
+}
+
+// GetSubType returns the subtype of the message, it is empty for plain
+// messages sent by users.
+func (message *Message) GetSubType() string {
+	return message.SubType
+}
+
+// GetBotID returns the bot which sent the message, if it was sent by a bot.
+func (message *Message) GetBotID() string {
+	return message.BotID
+}
+
+// GetThreadID returns the timestamp of the thread parent, or an empty string
+// if the message was not sent in a thread.
+func (message *Message) GetThreadID() string {
+	if message.ThreadTimeStamp == nil {
+		return ""
+	}
+
+	return message.ThreadTimeStamp.String()
+}
+
+// ReactionItem is the item a reaction belongs to, usually a message.
+type ReactionItem struct {
+	Type        string    `json:"type"`
+	Channel     string    `json:"channel,omitempty"`
+	TimeStamp   TimeStamp `json:"ts,omitempty"`
+	File        string    `json:"file,omitempty"`
+	FileComment string    `json:"file_comment,omitempty"`
+}
+
+// ReactionEvent holds the fields of reaction_added and reaction_removed events.
+type ReactionEvent struct {
+	CommonEvent
+	User           string       `json:"user"`
+	Reaction       string       `json:"reaction"`
+	ItemUser       string       `json:"item_user,omitempty"`
+	Item           ReactionItem `json:"item"`
+	EventTimeStamp TimeStamp    `json:"event_ts"`
+}
+
+// GetRoomID returns the channel of the reacted message.
+func (reaction ReactionEvent) GetRoomID() string {
+	return reaction.Item.Channel
+}
+
+// GetMessageID returns the timestamp of the reacted message.
+func (reaction ReactionEvent) GetMessageID() string {
+	return reaction.Item.TimeStamp.String()
+}
+
+// ReactionAdded is sent when a user adds a reaction to an item.
+type ReactionAdded struct {
+	ReactionEvent
+}
+
+// ReactionRemoved is sent when a user removes a reaction from an item.
+type ReactionRemoved struct {
+	ReactionEvent
+}
+
 // TeamMigrationStarted is sent when chat group is migrated between servers.
 type TeamMigrationStarted struct {
 	CommonEvent
@@ -59,33 +133,22 @@ type Pong struct {
 // DecodedEvent is just an empty interface that marks decoded event.
 type DecodedEvent interface{}
 
-// DecodeEvent decodes given payload and converts this to corresponding event structure.
+// DecodeEvent decodes given payload and converts this to corresponding event
+// structure, with the decoder registered for its type.
 func DecodeEvent(input json.RawMessage) (DecodedEvent, error) {
 	event := &CommonEvent{}
 	if err := json.Unmarshal(input, event); err != nil {
 		return nil, NewPayloadError(err.Error())
 	}
 
-	var mapping DecodedEvent
-
-	switch event.Type {
-	case HELLO:
-		mapping = &Hello{}
-	case MESSAGE:
-		mapping = &Message{}
-	case MIGRATION:
-		mapping = &TeamMigrationStarted{}
-	case PONG:
-		mapping = &Pong{}
-	case "":
+	if event.Type == "" {
 		return nil, NewEventTypeError("type is not given" + string(input))
-	default:
-		return nil, NewUnknownEventTypeError("received unknwon event." + string(input))
 	}
 
-	if err := json.Unmarshal(input, mapping); err != nil {
-		return nil, errors.New("error on JSON deserializing to mapped event" + string(input))
+	decoder, ok := decoderOf(event.Type)
+	if !ok {
+		return nil, NewUnknownEventTypeError("received unknwon event." + string(input))
 	}
 
-	return mapping, nil
+	return decoder(input)
 }
@@ -26,6 +26,24 @@ func TestDecodeMessage(t *testing.T) {
 	}
 }
 
+func TestDecodeThreadedMessage(t *testing.T) {
+	event, _ := DecodeEvent(json.RawMessage([]byte("{\"type\": \"message\", \"channel\": \"C2147483705\", \"user\": \"U2147483697\", \"text\": \"Hello, thread!\", \"ts\": \"1355517536.000001\", \"thread_ts\": \"1355517523.000005\"}")))
+
+	message, ok := event.(*Message)
+	if !ok {
+		t.Fatalf("unexpected event %#v", event)
+	}
+	if message.GetMessageID() != "1355517536.000001" {
+		t.Errorf("unexpected message id %s", message.GetMessageID())
+	}
+	if message.GetThreadID() != "1355517523.000005" {
+		t.Errorf("unexpected thread id %s", message.GetThreadID())
+	}
+	if message.GetSenderID() != "U2147483697" {
+		t.Errorf("unexpected sender %s", message.GetSenderID())
+	}
+}
+
 func TestDecodeUnknownEvent(t *testing.T) {
 	event, err := DecodeEvent(json.RawMessage([]byte("{\"type\": \"foo\", \"channel\": \"C2147483705\"}")))
 
@@ -37,3 +55,51 @@ func TestDecodeUnknownEvent(t *testing.T) {
 		t.Errorf("returned error is not type of UnknownEventTypeError, but is %#v", err.Error())
 	}
 }
+
+func TestDecodeReactions(t *testing.T) {
+	event, err := DecodeEvent(json.RawMessage([]byte(`{"type": "reaction_added", "user": "U1", "reaction": "white_check_mark", "item_user": "U2", "item": {"type": "message", "channel": "C1", "ts": "1360782400.498405"}, "event_ts": "1360782804.083113"}`)))
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	added, ok := event.(*ReactionAdded)
+	if !ok {
+		t.Fatalf("unexpected event %#v", event)
+	}
+	if added.User != "U1" || added.Reaction != "white_check_mark" || added.ItemUser != "U2" {
+		t.Errorf("unexpected reaction %#v", added)
+	}
+	if added.GetRoomID() != "C1" || added.GetMessageID() != "1360782400.498405" {
+		t.Errorf("unexpected item %#v", added.Item)
+	}
+
+	event, err = DecodeEvent(json.RawMessage([]byte(`{"type": "reaction_removed", "user": "U1", "reaction": "eyes", "item": {"type": "file", "file": "F1"}, "event_ts": "1360782804.083113"}`)))
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	removed, ok := event.(*ReactionRemoved)
+	if !ok {
+		t.Fatalf("unexpected event %#v", event)
+	}
+	if removed.Reaction != "eyes" || removed.Item.File != "F1" || removed.GetRoomID() != "" {
+		t.Errorf("unexpected reaction %#v", removed)
+	}
+}
+
+func TestDecodeWorkspaceEvents(t *testing.T) {
+	event, err := DecodeEvent(json.RawMessage([]byte(`{"type": "user_change", "user": {"id": "U1", "name": "ana", "tz": "Europe/Skopje", "profile": {"display_name": "Ana"}}}`)))
+	if change, ok := event.(*UserChange); err != nil || !ok || change.User.ID != "U1" || change.User.Profile.DisplayName != "Ana" {
+		t.Errorf("unexpected user change %#v %#v", event, err)
+	}
+
+	event, err = DecodeEvent(json.RawMessage([]byte(`{"type": "channel_rename", "channel": {"id": "C1", "name": "ops", "created": 1360782804}}`)))
+	if rename, ok := event.(*ChannelRename); err != nil || !ok || rename.Channel.Name != "ops" {
+		t.Errorf("unexpected channel rename %#v %#v", event, err)
+	}
+
+	event, err = DecodeEvent(json.RawMessage([]byte(`{"type": "member_joined_channel", "user": "U1", "channel": "C1", "channel_type": "C", "team": "T1"}`)))
+	if joined, ok := event.(*MemberJoinedChannel); err != nil || !ok || joined.User != "U1" || joined.Channel != "C1" {
+		t.Errorf("unexpected member joined %#v %#v", event, err)
+	}
+}
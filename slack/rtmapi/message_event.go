@@ -0,0 +1,155 @@
+package rtmapi
+
+import (
+	"encoding/json"
+	"sync"
+
+	"gitlab.com/kochevRisto/go-zha/slack/webapi"
+)
+
+// Message subtypes
+const (
+	// MESSAGE_CHANGED is sent when a message is edited
+	MESSAGE_CHANGED = "message_changed"
+	// MESSAGE_DELETED is sent when a message is deleted
+	MESSAGE_DELETED = "message_deleted"
+	// BOT_MESSAGE is a message sent by a bot integration
+	BOT_MESSAGE = "bot_message"
+	// THREAD_BROADCAST is a thread reply which is also sent to the channel
+	THREAD_BROADCAST = "thread_broadcast"
+	// CHANNEL_JOIN is sent when a user joins a channel
+	CHANNEL_JOIN = "channel_join"
+	// ME_MESSAGE is a /me message
+	ME_MESSAGE = "me_message"
+	// FILE_SHARE is a message with a shared file
+	FILE_SHARE = "file_share"
+)
+
+// MessageChanged is sent when a message is edited. Message holds the new
+// version of the message, its timestamp identifies the edited message.
+type MessageChanged struct {
+	IncomingChannelEvent
+	SubType         string    `json:"subtype"`
+	Hidden          bool      `json:"hidden"`
+	TimeStamp       TimeStamp `json:"ts"`
+	Message         Message   `json:"message"`
+	PreviousMessage *Message  `json:"previous_message,omitempty"`
+}
+
+// GetRoomID returns the channel of the edited message.
+func (changed *MessageChanged) GetRoomID() string {
+	return changed.Channel
+}
+
+// MessageDeleted is sent when a message is deleted.
+type MessageDeleted struct {
+	IncomingChannelEvent
+	SubType          string    `json:"subtype"`
+	Hidden           bool      `json:"hidden"`
+	TimeStamp        TimeStamp `json:"ts"`
+	DeletedTimeStamp TimeStamp `json:"deleted_ts"`
+	PreviousMessage  *Message  `json:"previous_message,omitempty"`
+}
+
+// GetRoomID returns the channel of the deleted message.
+func (deleted *MessageDeleted) GetRoomID() string {
+	return deleted.Channel
+}
+
+// BotMessage is a message sent by a bot integration.
+type BotMessage struct {
+	Message
+	Username string `json:"username,omitempty"`
+}
+
+// ThreadBroadcast is a thread reply which is also sent to the channel.
+type ThreadBroadcast struct {
+	Message
+	Root *Message `json:"root,omitempty"`
+}
+
+// ChannelJoin is sent when a user joins a channel.
+type ChannelJoin struct {
+	Message
+	Inviter string `json:"inviter,omitempty"`
+}
+
+// MeMessage is a /me message.
+type MeMessage struct {
+	Message
+}
+
+// FileShare is a message with files shared by a user.
+type FileShare struct {
+	Message
+	Files  []webapi.File `json:"files"`
+	Upload bool          `json:"upload"`
+}
+
+// SystemMessage is a message of a subtype without a registered structure,
+// e.g. channel_topic, channel_purpose or bot_add. Slack posts them about
+// changes of the channel, so they are not decoded into Message and do not
+// reach the handlers of received messages.
+type SystemMessage struct {
+	IncomingChannelEvent
+	SubType   string    `json:"subtype"`
+	User      string    `json:"user,omitempty"`
+	Text      string    `json:"text"`
+	TimeStamp TimeStamp `json:"ts"`
+}
+
+// GetRoomID returns the channel the message was posted in.
+func (message *SystemMessage) GetRoomID() string {
+	return message.Channel
+}
+
+var (
+	subtypesMu sync.RWMutex
+	subtypes   = map[string]func() DecodedEvent{
+		MESSAGE_CHANGED:  func() DecodedEvent { return &MessageChanged{} },
+		MESSAGE_DELETED:  func() DecodedEvent { return &MessageDeleted{} },
+		BOT_MESSAGE:      func() DecodedEvent { return &BotMessage{} },
+		THREAD_BROADCAST: func() DecodedEvent { return &ThreadBroadcast{} },
+		CHANNEL_JOIN:     func() DecodedEvent { return &ChannelJoin{} },
+		ME_MESSAGE:       func() DecodedEvent { return &MeMessage{} },
+		FILE_SHARE:       func() DecodedEvent { return &FileShare{} },
+	}
+)
+
+// RegisterMessageSubtype registers the structure messages of the subtype are
+// decoded into. Messages of unknown subtypes are decoded into SystemMessage.
+func RegisterMessageSubtype(subtype string, newEvent func() DecodedEvent) {
+	subtypesMu.Lock()
+	defer subtypesMu.Unlock()
+
+	if newEvent == nil {
+		delete(subtypes, subtype)
+		return
+	}
+
+	subtypes[subtype] = newEvent
+}
+
+// decodeMessage decodes message events into the structure of their subtype.
+func decodeMessage(input json.RawMessage) (DecodedEvent, error) {
+	message := &struct {
+		SubType string `json:"subtype"`
+	}{}
+	if err := json.Unmarshal(input, message); err != nil {
+		return nil, NewPayloadError(err.Error())
+	}
+
+	subtypesMu.RLock()
+	newEvent, ok := subtypes[message.SubType]
+	subtypesMu.RUnlock()
+
+	switch {
+	case ok:
+	case message.SubType == "":
+		newEvent = func() DecodedEvent { return &Message{} }
+	default:
+		newEvent = func() DecodedEvent { return &SystemMessage{} }
+	}
+
+	return DecodeInto(newEvent)(input)
+}
@@ -0,0 +1,82 @@
+package rtmapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeMessageSubtypes(t *testing.T) {
+	payloads := map[string]DecodedEvent{
+		`{"type": "message", "channel": "C1", "user": "U1", "text": "hi", "ts": "1.1"}`:                                                         &Message{},
+		`{"type": "message", "subtype": "bot_message", "channel": "C1", "bot_id": "B1", "username": "ci", "text": "build passed", "ts": "1.1"}`: &BotMessage{},
+		`{"type": "message", "subtype": "thread_broadcast", "channel": "C1", "user": "U1", "text": "hi", "ts": "1.2", "thread_ts": "1.1"}`:      &ThreadBroadcast{},
+		`{"type": "message", "subtype": "channel_join", "channel": "C1", "user": "U1", "text": "<@U1> has joined the channel", "ts": "1.1"}`:    &ChannelJoin{},
+		`{"type": "message", "subtype": "me_message", "channel": "C1", "user": "U1", "text": "waves", "ts": "1.1"}`:                             &MeMessage{},
+		`{"type": "message", "subtype": "file_share", "channel": "C1", "user": "U1", "files": [{"id": "F1"}], "upload": true, "ts": "1.1"}`:     &FileShare{},
+		`{"type": "message", "subtype": "channel_topic", "channel": "C1", "user": "U1", "text": "set the topic", "ts": "1.1"}`:                  &SystemMessage{},
+	}
+
+	for payload, expected := range payloads {
+		event, err := DecodeEvent(json.RawMessage(payload))
+		if err != nil {
+			t.Errorf("unexpected error %#v for %s", err, payload)
+			continue
+		}
+
+		if reflect.TypeOf(event) != reflect.TypeOf(expected) {
+			t.Errorf("expected %T, got %T for %s", expected, event, payload)
+		}
+	}
+
+	event, _ := DecodeEvent(json.RawMessage(`{"type": "message", "subtype": "bot_message", "channel": "C1", "bot_id": "B1", "username": "ci", "text": "build passed", "ts": "1.1"}`))
+	if bot := event.(*BotMessage); bot.GetBotID() != "B1" || bot.GetMessage() != "build passed" || bot.GetRoomID() != "C1" || bot.Username != "ci" {
+		t.Errorf("unexpected bot message %#v", bot)
+	}
+}
+
+func TestDecodeMessageChanged(t *testing.T) {
+	event, err := DecodeEvent(json.RawMessage(`{
+		"type": "message",
+		"subtype": "message_changed",
+		"hidden": true,
+		"channel": "C1",
+		"ts": "1358878755.000001",
+		"message": {"type": "message", "user": "U1", "text": "Hello, world!", "ts": "1355517523.000005", "edited": {"user": "U1", "ts": "1358878755.000001"}},
+		"previous_message": {"type": "message", "user": "U1", "text": "Helo, world", "ts": "1355517523.000005"}
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	changed, ok := event.(*MessageChanged)
+	if !ok {
+		t.Fatalf("unexpected event %#v", event)
+	}
+	if changed.GetRoomID() != "C1" || changed.Message.Text != "Hello, world!" || changed.Message.GetMessageID() != "1355517523.000005" {
+		t.Errorf("unexpected message %#v", changed.Message)
+	}
+	if changed.Message.Edited == nil || changed.Message.Edited.User != "U1" || changed.PreviousMessage.Text != "Helo, world" {
+		t.Errorf("unexpected edit %#v", changed)
+	}
+
+	event, err = DecodeEvent(json.RawMessage(`{"type": "message", "subtype": "message_deleted", "hidden": true, "channel": "C1", "ts": "1358878755.000001", "deleted_ts": "1358878749.000002"}`))
+	if deleted, ok := event.(*MessageDeleted); err != nil || !ok || deleted.DeletedTimeStamp.String() != "1358878749.000002" {
+		t.Errorf("unexpected event %#v %#v", event, err)
+	}
+}
+
+type pinnedItem struct {
+	Message
+	ItemType string `json:"item_type"`
+}
+
+func TestRegisterMessageSubtype(t *testing.T) {
+	RegisterMessageSubtype("pinned_item", func() DecodedEvent { return &pinnedItem{} })
+	defer RegisterMessageSubtype("pinned_item", nil)
+
+	event, err := DecodeEvent(json.RawMessage(`{"type": "message", "subtype": "pinned_item", "channel": "C1", "user": "U1", "item_type": "F", "ts": "1.1"}`))
+	if pinned, ok := event.(*pinnedItem); err != nil || !ok || pinned.ItemType != "F" {
+		t.Errorf("unexpected event %#v %#v", event, err)
+	}
+}
@@ -14,16 +14,17 @@ type OutgoingCommonEvent struct {
 // OutgoingMessage represents a simple message sent from client to Slack
 type OutgoingMessage struct {
 	OutgoingCommonEvent
-	ID      uint   `json:"id"`
-	Channel string `json:"channel"`
-	Text    string `json:"text"`
+	Channel  string `json:"channel"`
+	Text     string `json:"text"`
+	ThreadTS string `json:"thread_ts,omitempty"`
 }
 
 // NewOutgoingMessage creates new OutgoingMessage instace
 func NewOutgoingMessage(eventID *OutgoingEventID, message *TextMessage) *OutgoingMessage {
 	return &OutgoingMessage{
-		Channel: message.channel,
-		Text:    message.text,
+		Channel:  message.channel,
+		Text:     message.text,
+		ThreadTS: message.threadTS,
 		OutgoingCommonEvent: OutgoingCommonEvent{
 			ID:          eventID.Next(),
 			CommonEvent: CommonEvent{Type: MESSAGE},
@@ -0,0 +1,22 @@
+package rtmapi
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOutgoingMessageJSON(t *testing.T) {
+	eventID := NewOutgoingEventID()
+	eventID.Next()
+
+	message := NewOutgoingMessage(eventID, NewThreadTextMessage("C1", "1355517523.000005", "hello"))
+	payload, err := json.Marshal(message)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	expected := `{"type":"message","id":2,"channel":"C1","text":"hello","thread_ts":"1355517523.000005"}`
+	if string(payload) != expected {
+		t.Errorf("expected %s, got %s", expected, payload)
+	}
+}
@@ -0,0 +1,85 @@
+package rtmapi
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// Decoder decodes the payload of an event into its event structure.
+type Decoder func(json.RawMessage) (DecodedEvent, error)
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[EventType]Decoder{
+		HELLO:                 DecodeInto(func() DecodedEvent { return &Hello{} }),
+		MESSAGE:               decodeMessage,
+		MIGRATION:             DecodeInto(func() DecodedEvent { return &TeamMigrationStarted{} }),
+		PONG:                  DecodeInto(func() DecodedEvent { return &Pong{} }),
+		GOODBYE:               DecodeInto(func() DecodedEvent { return &Goodbye{} }),
+		ERROR:                 DecodeInto(func() DecodedEvent { return &ErrorEvent{} }),
+		RECONNECT_URL:         DecodeInto(func() DecodedEvent { return &ReconnectURL{} }),
+		REACTION_ADDED:        DecodeInto(func() DecodedEvent { return &ReactionAdded{} }),
+		REACTION_REMOVED:      DecodeInto(func() DecodedEvent { return &ReactionRemoved{} }),
+		PIN_ADDED:             DecodeInto(func() DecodedEvent { return &PinAdded{} }),
+		PIN_REMOVED:           DecodeInto(func() DecodedEvent { return &PinRemoved{} }),
+		FILE_SHARED:           DecodeInto(func() DecodedEvent { return &FileShared{} }),
+		USER_TYPING:           DecodeInto(func() DecodedEvent { return &UserTyping{} }),
+		PRESENCE_CHANGE:       DecodeInto(func() DecodedEvent { return &PresenceChange{} }),
+		USER_CHANGE:           DecodeInto(func() DecodedEvent { return &UserChange{} }),
+		TEAM_JOIN:             DecodeInto(func() DecodedEvent { return &TeamJoin{} }),
+		CHANNEL_CREATED:       DecodeInto(func() DecodedEvent { return &ChannelCreated{} }),
+		CHANNEL_JOINED:        DecodeInto(func() DecodedEvent { return &ChannelJoined{} }),
+		CHANNEL_LEFT:          DecodeInto(func() DecodedEvent { return &ChannelLeft{} }),
+		CHANNEL_RENAME:        DecodeInto(func() DecodedEvent { return &ChannelRename{} }),
+		CHANNEL_DELETED:       DecodeInto(func() DecodedEvent { return &ChannelDeleted{} }),
+		CHANNEL_ARCHIVE:       DecodeInto(func() DecodedEvent { return &ChannelArchive{} }),
+		CHANNEL_UNARCHIVE:     DecodeInto(func() DecodedEvent { return &ChannelUnarchive{} }),
+		GROUP_RENAME:          DecodeInto(func() DecodedEvent { return &GroupRename{} }),
+		IM_CREATED:            DecodeInto(func() DecodedEvent { return &IMCreated{} }),
+		MEMBER_JOINED_CHANNEL: DecodeInto(func() DecodedEvent { return &MemberJoinedChannel{} }),
+		MEMBER_LEFT_CHANNEL:   DecodeInto(func() DecodedEvent { return &MemberLeftChannel{} }),
+	}
+)
+
+// RegisterDecoder registers the decoder of the event type, e.g. for events
+// rtmapi does not know. It replaces the decoder of known types, a nil decoder
+// removes the type.
+func RegisterDecoder(eventType EventType, decoder Decoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+
+	if decoder == nil {
+		delete(decoders, eventType)
+		return
+	}
+
+	decoders[eventType] = decoder
+}
+
+// RegisterEvent registers the structure events of the type are decoded into.
+// newEvent has to return a pointer.
+func RegisterEvent(eventType EventType, newEvent func() DecodedEvent) {
+	RegisterDecoder(eventType, DecodeInto(newEvent))
+}
+
+// DecodeInto returns a decoder which unmarshals the payload into the
+// structure returned by newEvent.
+func DecodeInto(newEvent func() DecodedEvent) Decoder {
+	return func(input json.RawMessage) (DecodedEvent, error) {
+		mapping := newEvent()
+		if err := json.Unmarshal(input, mapping); err != nil {
+			return nil, errors.New("error on JSON deserializing to mapped event" + string(input))
+		}
+
+		return mapping, nil
+	}
+}
+
+func decoderOf(eventType EventType) (Decoder, bool) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+
+	decoder, ok := decoders[eventType]
+	return decoder, ok
+}
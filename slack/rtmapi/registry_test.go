@@ -0,0 +1,81 @@
+package rtmapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeCatalogue(t *testing.T) {
+	payloads := map[string]DecodedEvent{
+		`{"type": "goodbye"}`: &Goodbye{},
+		`{"type": "error", "error": {"code": 1, "msg": "Socket URL has expired"}}`:                                &ErrorEvent{},
+		`{"type": "reconnect_url", "url": "wss://example.com/websocket"}`:                                         &ReconnectURL{},
+		`{"type": "user_typing", "channel": "C1", "user": "U1"}`:                                                  &UserTyping{},
+		`{"type": "presence_change", "users": ["U1", "U2"], "presence": "away"}`:                                  &PresenceChange{},
+		`{"type": "channel_joined", "channel": {"id": "C1", "name": "ops"}}`:                                      &ChannelJoined{},
+		`{"type": "channel_left", "channel": "C1"}`:                                                               &ChannelLeft{},
+		`{"type": "im_created", "user": "U1", "channel": {"id": "D1"}}`:                                           &IMCreated{},
+		`{"type": "pin_added", "user": "U1", "channel_id": "C1", "item": {"type": "message"}, "event_ts": "1.2"}`: &PinAdded{},
+		`{"type": "pin_removed", "user": "U1", "channel_id": "C1", "item": {"type": "file"}, "event_ts": "1.2"}`:  &PinRemoved{},
+		`{"type": "file_shared", "file_id": "F1", "file": {"id": "F1"}, "user_id": "U1", "event_ts": "1.2"}`:      &FileShared{},
+	}
+
+	for payload, expected := range payloads {
+		event, err := DecodeEvent(json.RawMessage(payload))
+		if err != nil {
+			t.Errorf("unexpected error %#v for %s", err, payload)
+			continue
+		}
+
+		if reflect.TypeOf(event) != reflect.TypeOf(expected) {
+			t.Errorf("expected %T, got %T for %s", expected, event, payload)
+		}
+	}
+
+	event, _ := DecodeEvent(json.RawMessage(`{"type": "error", "error": {"code": 1, "msg": "Socket URL has expired"}}`))
+	if e := event.(*ErrorEvent); e.Error.Code != 1 || e.Error.Msg != "Socket URL has expired" {
+		t.Errorf("unexpected error event %#v", e)
+	}
+
+	event, _ = DecodeEvent(json.RawMessage(`{"type": "pin_added", "user": "U1", "channel_id": "C1", "item": {"type": "message", "message": {"text": "runbook"}}, "event_ts": "1.2"}`))
+	if pin := event.(*PinAdded); pin.GetRoomID() != "C1" || pin.Item.Message == nil || pin.Item.Message.Text != "runbook" {
+		t.Errorf("unexpected pin %#v", pin)
+	}
+}
+
+type dndUpdated struct {
+	CommonEvent
+	User string `json:"user"`
+}
+
+func TestRegisterEvent(t *testing.T) {
+	payload := json.RawMessage(`{"type": "dnd_updated_user", "user": "U1"}`)
+	if _, err := DecodeEvent(payload); err == nil {
+		t.Fatal("expected unknown event type")
+	}
+
+	RegisterEvent("dnd_updated_user", func() DecodedEvent { return &dndUpdated{} })
+	defer RegisterDecoder("dnd_updated_user", nil)
+
+	event, err := DecodeEvent(payload)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if dnd, ok := event.(*dndUpdated); !ok || dnd.User != "U1" {
+		t.Errorf("unexpected event %#v", event)
+	}
+}
+
+func TestRegisterDecoder(t *testing.T) {
+	RegisterDecoder(USER_TYPING, func(json.RawMessage) (DecodedEvent, error) {
+		return "typing", nil
+	})
+	defer RegisterEvent(USER_TYPING, func() DecodedEvent { return &UserTyping{} })
+
+	event, err := DecodeEvent(json.RawMessage(`{"type": "user_typing", "channel": "C1", "user": "U1"}`))
+	if err != nil || event != "typing" {
+		t.Errorf("unexpected event %#v %#v", event, err)
+	}
+}
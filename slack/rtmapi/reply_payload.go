@@ -4,10 +4,17 @@ import "encoding/json"
 
 // WebSocketReply is passed from slack as a reply to client message
 type WebSocketReply struct {
-	OK *bool `json:"ok"`
-	ReplyTo uint `json:"reply_to"`
-	TimeStamp TimeStamp `json:"ts"`
-	Text string `json:"text"`
+	OK        *bool       `json:"ok"`
+	ReplyTo   uint        `json:"reply_to"`
+	TimeStamp TimeStamp   `json:"ts"`
+	Text      string      `json:"text"`
+	Error     *ReplyError `json:"error,omitempty"`
+}
+
+// ReplyError describes why slack rejected a client message
+type ReplyError struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
 }
 
 // DecodeReply parses given reply payload from slack
@@ -22,4 +29,4 @@ func DecodeReply(input json.RawMessage) (*WebSocketReply, error) {
 	}
 
 	return reply, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,98 @@
+package rtmapi
+
+import "gitlab.com/kochevRisto/go-zha/slack/webapi"
+
+// UserChange is sent when a user's profile changes.
+type UserChange struct {
+	CommonEvent
+	User webapi.User `json:"user"`
+}
+
+// TeamJoin is sent when a new user joins the team.
+type TeamJoin struct {
+	CommonEvent
+	User webapi.User `json:"user"`
+}
+
+// ChannelCreated is sent when a new channel is created.
+type ChannelCreated struct {
+	CommonEvent
+	Channel webapi.Channel `json:"channel"`
+}
+
+// ChannelJoined is sent when the bot joins a channel.
+type ChannelJoined struct {
+	CommonEvent
+	Channel webapi.Channel `json:"channel"`
+}
+
+// ChannelLeft is sent when the bot leaves a channel.
+type ChannelLeft struct {
+	CommonEvent
+	Channel string `json:"channel"`
+}
+
+// RenamedChannel is the channel of a rename event.
+type RenamedChannel struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Created int64  `json:"created"`
+}
+
+// ChannelRename is sent when a channel is renamed.
+type ChannelRename struct {
+	CommonEvent
+	Channel RenamedChannel `json:"channel"`
+}
+
+// GroupRename is sent when a private channel is renamed.
+type GroupRename struct {
+	CommonEvent
+	Channel RenamedChannel `json:"channel"`
+}
+
+// ChannelDeleted is sent when a channel is deleted.
+type ChannelDeleted struct {
+	CommonEvent
+	Channel string `json:"channel"`
+}
+
+// ChannelArchive is sent when a channel is archived.
+type ChannelArchive struct {
+	CommonEvent
+	Channel string `json:"channel"`
+	User    string `json:"user"`
+}
+
+// ChannelUnarchive is sent when a channel is unarchived.
+type ChannelUnarchive struct {
+	CommonEvent
+	Channel string `json:"channel"`
+	User    string `json:"user"`
+}
+
+// IMCreated is sent when a direct message channel is opened.
+type IMCreated struct {
+	CommonEvent
+	User    string    `json:"user"`
+	Channel webapi.IM `json:"channel"`
+}
+
+// MemberJoinedChannel is sent when a user joins a channel.
+type MemberJoinedChannel struct {
+	CommonEvent
+	User        string `json:"user"`
+	Channel     string `json:"channel"`
+	ChannelType string `json:"channel_type"`
+	Team        string `json:"team"`
+	Inviter     string `json:"inviter,omitempty"`
+}
+
+// MemberLeftChannel is sent when a user leaves a channel.
+type MemberLeftChannel struct {
+	CommonEvent
+	User        string `json:"user"`
+	Channel     string `json:"channel"`
+	ChannelType string `json:"channel_type"`
+	Team        string `json:"team"`
+}
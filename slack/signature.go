@@ -0,0 +1,80 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	signatureVersion = "v0"
+	// maxRequestAge protects against replayed requests
+	maxRequestAge = 5 * time.Minute
+	// maxRequestSize limits the body of requests sent by slack
+	maxRequestSize = 1 << 20
+)
+
+var (
+	// ErrMissingSigningSecret is returned when requests from slack can not be
+	// verified because no signing secret is configured.
+	ErrMissingSigningSecret = errors.New("signing secret is not configured")
+	// ErrInvalidSignature is returned when a request was not signed by slack.
+	ErrInvalidSignature = errors.New("request signature is invalid")
+	// ErrExpiredTimestamp is returned when a request is too old.
+	ErrExpiredTimestamp = errors.New("request timestamp is expired")
+)
+
+// VerifySignature checks that the request body was signed by slack with the
+// signing secret of the app.
+// ex. https://api.slack.com/authentication/verifying-requests-from-slack
+func VerifySignature(header http.Header, body []byte, signingSecret string, now time.Time) error {
+	if signingSecret == "" {
+		return ErrMissingSigningSecret
+	}
+
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > maxRequestAge || age < -maxRequestAge {
+		return ErrExpiredTimestamp
+	}
+
+	expected := []byte(sign(signingSecret, timestamp, body))
+	if !hmac.Equal(expected, []byte(header.Get("X-Slack-Signature"))) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func sign(signingSecret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte(signatureVersion + ":" + timestamp + ":"))
+	mac.Write(body)
+
+	return signatureVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// readVerifiedBody reads the body of a request sent by slack and verifies
+// its signature.
+func readVerifiedBody(r *http.Request, signingSecret string) ([]byte, error) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(nil, r.Body, maxRequestSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := VerifySignature(r.Header, body, signingSecret, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
@@ -0,0 +1,44 @@
+package slack
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func signedHeader(secret string, body []byte, at time.Time) http.Header {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+
+	header := http.Header{}
+	header.Set("X-Slack-Request-Timestamp", timestamp)
+	header.Set("X-Slack-Signature", sign(secret, timestamp, body))
+	return header
+}
+
+func TestVerifySignature(t *testing.T) {
+	// example from https://api.slack.com/authentication/verifying-requests-from-slack
+	body := []byte("token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J&team_domain=testteamnow&channel_id=G8PSS9T3V&channel_name=foobar&user_id=U2CERLKJA&user_name=roadrunner&command=%2Fwebhook-collect&text=&response_url=https%3A%2F%2Fhooks.slack.com%2Fcommands%2FT1DC2JH3J%2F397700885554%2F96rGlfmibIGlgcZRskXaIFfN&trigger_id=398738663015.47445629121.803a0bc887a14d10d2c447fce8b6703c")
+	header := http.Header{}
+	header.Set("X-Slack-Request-Timestamp", "1531420618")
+	header.Set("X-Slack-Signature", "v0=a2114d57b48eac39b9ad189dd8316235a7b4a8d21a10bd27519666489c69b503")
+
+	secret := "8f742231b10e8888abcd99yyyzzz85a5"
+	now := time.Unix(1531420618, 0)
+
+	if err := VerifySignature(header, body, secret, now); err != nil {
+		t.Errorf("expected valid signature, got %#v", err)
+	}
+
+	if err := VerifySignature(header, body, "wrong", now); err != ErrInvalidSignature {
+		t.Errorf("expected invalid signature, got %#v", err)
+	}
+
+	if err := VerifySignature(header, body, secret, now.Add(10*time.Minute)); err != ErrExpiredTimestamp {
+		t.Errorf("expected expired timestamp, got %#v", err)
+	}
+
+	if err := VerifySignature(header, body, "", now); err != ErrMissingSigningSecret {
+		t.Errorf("expected missing secret, got %#v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package slack
+
+import (
+	"encoding/json"
+	"time"
+
+	"gitlab.com/kochevRisto/go-zha"
+	"gitlab.com/kochevRisto/go-zha/slack/eventsapi"
+	"gitlab.com/kochevRisto/go-zha/slack/retry"
+	"gitlab.com/kochevRisto/go-zha/slack/rtmapi"
+	"gitlab.com/kochevRisto/go-zha/slack/socketmode"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+const socketModeRetryInterval = 5 * time.Second
+
+// runSocketMode keeps a Socket Mode connection open until the adapter is
+// closed. Slack asks to reconnect with disconnect envelopes from time to time.
+func (s *Adapter) runSocketMode(b *zha.Brain) {
+	for {
+		select {
+		case <-s.stopAll:
+			return
+		default:
+		}
+
+		conn, err := s.connectSocketMode()
+		if err != nil {
+			s.logger.Error("failed to open socket mode connection", zap.Error(err))
+
+			select {
+			case <-s.stopAll:
+				return
+			case <-time.After(socketModeRetryInterval):
+			}
+
+			continue
+		}
+
+		s.receiveEnvelopes(b, conn)
+
+		s.mu.Lock()
+		s.socketConnection = nil
+		s.mu.Unlock()
+
+		if err := conn.Close(); err != nil {
+			s.logger.Debug("error on socket mode connection close", zap.Error(err))
+		}
+	}
+}
+
+func (s *Adapter) connectSocketMode() (*websocket.Conn, error) {
+	var conn *websocket.Conn
+	err := retry.Interval(10, func() error {
+		auth, err := s.WebAPIClient.AuthTestContext(s.ctx)
+		if err != nil {
+			return err
+		}
+
+		connection, err := s.AppWebAPIClient.AppsConnectionsOpenContext(s.ctx)
+		if err != nil {
+			return err
+		}
+
+		c, err := s.SocketModeClient.Connect(connection.URL)
+		if err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		s.selfID = auth.UserID
+		s.socketConnection = c
+		s.mu.Unlock()
+
+		conn = c
+		return nil
+	}, 500*time.Millisecond)
+
+	return conn, err
+}
+
+// receiveEnvelopes acknowledges and handles envelopes until the connection
+// is closed or slack asks to reconnect.
+func (s *Adapter) receiveEnvelopes(b *zha.Brain, conn *websocket.Conn) {
+	for {
+		envelope, err := s.SocketModeClient.ReceiveEnvelope(conn)
+		if err != nil {
+			switch err.(type) {
+			case *rtmapi.PayloadError, *rtmapi.EventTypeError:
+				s.logger.Warn("malformed envelope was passed.", zap.Error(err))
+				continue
+			default:
+				s.logger.Info("socket mode connection lost", zap.Error(err))
+				return
+			}
+		}
+
+		switch envelope.Type {
+		case socketmode.HELLO:
+			s.logger.Debug("socket mode connection established", zap.Int("connections", envelope.NumConnections))
+		case socketmode.DISCONNECT:
+			s.logger.Info("socket mode disconnect requested", zap.String("reason", envelope.Reason))
+			return
+		case socketmode.INTERACTIVE:
+			s.handleInteractiveEnvelope(conn, envelope)
+		default:
+			s.ack(conn, envelope, s.ackPayload(envelope))
+			s.handleEnvelope(b, envelope)
+		}
+	}
+}
+
+// ack acknowledges the envelope, if slack expects it to be acknowledged.
+func (s *Adapter) ack(conn *websocket.Conn, envelope *socketmode.Envelope, payload interface{}) {
+	if envelope.EnvelopeID == "" {
+		return
+	}
+
+	if err := s.SocketModeClient.Ack(conn, envelope.EnvelopeID, payload); err != nil {
+		s.logger.Error("failed to acknowledge envelope", zap.String("envelope_id", envelope.EnvelopeID), zap.Error(err))
+	}
+}
+
+// handleInteractiveEnvelope emits the events of the interaction. View
+// submissions are acknowledged with the response set by the handlers once
+// they ran, without blocking the envelopes which follow.
+func (s *Adapter) handleInteractiveEnvelope(conn *websocket.Conn, envelope *socketmode.Envelope) {
+	interaction, err := DecodeInteraction(envelope.Payload)
+	if err != nil {
+		s.ack(conn, envelope, nil)
+		s.logger.Warn("malformed interaction payload was passed.", zap.Error(err))
+		return
+	}
+
+	pending := s.handleInteraction(interaction)
+	if pending == nil {
+		s.ack(conn, envelope, nil)
+		return
+	}
+
+	go func() {
+		var payload interface{}
+		if response := s.awaitResponse(s.ctx, pending); response != nil {
+			payload = response
+		}
+
+		s.ack(conn, envelope, payload)
+	}()
+}
+
+// ackPayload returns the payload the envelope is acknowledged with.
+func (s *Adapter) ackPayload(envelope *socketmode.Envelope) interface{} {
+	if envelope.Type == socketmode.SLASHCOMMANDS && envelope.AcceptsResponsePayload {
+		if ack := s.commandAck(); ack != nil {
+			return ack
+		}
+	}
+
+	return nil
+}
+
+func (s *Adapter) handleEnvelope(b *zha.Brain, envelope *socketmode.Envelope) {
+	switch envelope.Type {
+	case socketmode.EVENTS:
+		callback, err := eventsapi.DecodeCallback(envelope.Payload)
+		if err != nil {
+			s.logger.Warn("malformed events payload was passed.", zap.Error(err))
+			return
+		}
+
+		event, err := callback.DecodeEvent()
+		if err != nil {
+			s.logger.Debug("unhandled event", zap.Error(err))
+			return
+		}
+
+		s.emitEvent(b, event)
+	case socketmode.SLASHCOMMANDS:
+		command := &SlashCommand{}
+		if err := json.Unmarshal(envelope.Payload, command); err != nil {
+			s.logger.Warn("malformed command payload was passed.", zap.Error(err))
+			return
+		}
+
+		s.handleCommand(command)
+	default:
+		s.logger.Debug("unhandled envelope", zap.String("type", string(envelope.Type)))
+	}
+}
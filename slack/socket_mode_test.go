@@ -0,0 +1,135 @@
+package slack
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"gitlab.com/kochevRisto/go-zha"
+	"gitlab.com/kochevRisto/go-zha/slack/socketmode"
+	"gitlab.com/kochevRisto/go-zha/slack/webapi"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+// mockSocketModeOpen makes apps.connections.open return the URL of server
+func mockSocketModeOpen(server *httptest.Server) {
+	authResponder, _ := httpmock.NewJsonResponder(200, &webapi.AuthTest{
+		APIResponse: webapi.APIResponse{OK: true},
+		UserID:      "U0BOT",
+	})
+	httpmock.RegisterResponder("POST", "https://slack.com/api/auth.test", authResponder)
+
+	openResponder, _ := httpmock.NewJsonResponder(200, &webapi.AppsConnectionsOpen{
+		APIResponse: webapi.APIResponse{OK: true},
+		URL:         "ws://" + server.Listener.Addr().String(),
+	})
+	httpmock.RegisterResponder("POST", "https://slack.com/api/apps.connections.open", openResponder)
+}
+
+func TestSocketMode(t *testing.T) {
+	acks := make(chan string, 1)
+	server := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		defer ws.Close()
+
+		websocket.Message.Send(ws, `{"type": "hello", "num_connections": 1}`)
+		websocket.Message.Send(ws, `{"type": "events_api", "envelope_id": "env-1", "payload": {"type": "event_callback", "event_id": "Ev1", "event": {"type": "message", "channel": "D1", "user": "U1", "text": "hello", "ts": "1355517523.000005"}}}`)
+
+		ack := &socketmode.Ack{}
+		if err := websocket.JSON.Receive(ws, ack); err == nil {
+			acks <- ack.EnvelopeID
+		}
+
+		// keep the connection open until the adapter closes it
+		websocket.JSON.Receive(ws, ack)
+	}))
+	defer server.Close()
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	mockSocketModeOpen(server)
+
+	brain := zha.NewBrain(zap.NewNop(), time.Second)
+	received := make(chan zha.ReciveMessageEvent, 1)
+	brain.RegisterHandler(func(evt zha.ReciveMessageEvent) {
+		received <- evt
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go brain.Process(ctx)
+
+	adapter := NewSlackAdapter(&Config{Token: "xoxb-test", SocketMode: true, AppToken: "xapp-test"})
+	adapter.Register(brain)
+	defer adapter.Close()
+
+	select {
+	case envelopeID := <-acks:
+		if envelopeID != "env-1" {
+			t.Errorf("unexpected ack %q", envelopeID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("envelope was not acknowledged")
+	}
+
+	select {
+	case evt := <-received:
+		if evt.Text != "hello" || evt.ChannelD != "D1" || evt.UserID != "U1" || !evt.Direct {
+			t.Errorf("unexpected event %#v", evt)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message event was not emitted")
+	}
+
+	if adapter.SelfID() != "U0BOT" {
+		t.Errorf("unexpected self id %q", adapter.SelfID())
+	}
+}
+
+func TestSocketModeViewSubmissionAck(t *testing.T) {
+	acks := make(chan *socketmode.Ack, 1)
+	server := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		defer ws.Close()
+
+		websocket.Message.Send(ws, `{"type": "hello", "num_connections": 1}`)
+		websocket.Message.Send(ws, `{"type": "interactive", "envelope_id": "env-1", "accepts_response_payload": true, "payload": {"type": "view_submission", "user": {"id": "U1"}, "view": {"id": "V1", "type": "modal", "callback_id": "deploy", "blocks": []}}}`)
+
+		ack := &socketmode.Ack{}
+		if err := websocket.JSON.Receive(ws, ack); err == nil {
+			acks <- ack
+		}
+
+		// keep the connection open until the adapter closes it
+		websocket.JSON.Receive(ws, ack)
+	}))
+	defer server.Close()
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	mockSocketModeOpen(server)
+
+	bot := zha.NewBot("zha")
+	OnViewSubmission(bot, "deploy", func(i Interaction) error {
+		return i.SetResponse(&ViewSubmissionResponse{ResponseAction: ResponseActionClear})
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bot.Brain.Process(ctx)
+
+	adapter := NewSlackAdapter(&Config{Token: "xoxb-test", SocketMode: true, AppToken: "xapp-test"})
+	adapter.Register(bot.Brain)
+	defer adapter.Close()
+
+	select {
+	case ack := <-acks:
+		payload, _ := ack.Payload.(map[string]interface{})
+		if ack.EnvelopeID != "env-1" || payload["response_action"] != ResponseActionClear {
+			t.Errorf("unexpected ack %#v", ack)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("envelope was not acknowledged")
+	}
+}
@@ -0,0 +1,46 @@
+package socketmode
+
+import (
+	"encoding/json"
+
+	"gitlab.com/kochevRisto/go-zha/slack/rtmapi"
+	"golang.org/x/net/websocket"
+)
+
+// Client struct
+type Client struct{}
+
+// NewClient creates new Client
+func NewClient() *Client {
+	return &Client{}
+}
+
+// Connect web socket connection
+func (c *Client) Connect(url string) (*websocket.Conn, error) {
+	return websocket.Dial(url, "", "http://localhost")
+}
+
+// ReceiveEnvelope receives the next envelope from the websocket
+func (c *Client) ReceiveEnvelope(conn *websocket.Conn) (*Envelope, error) {
+	payload, err := rtmapi.ReceivePayload(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := &Envelope{}
+	if err := json.Unmarshal(payload, envelope); err != nil {
+		return nil, rtmapi.NewPayloadError(err.Error())
+	}
+
+	if envelope.Type == "" {
+		return nil, rtmapi.NewEventTypeError("type is not given" + string(payload))
+	}
+
+	return envelope, nil
+}
+
+// Ack acknowledges the envelope with the given ID, payload is optional and
+// only used for envelopes which accept a response payload.
+func (c *Client) Ack(conn *websocket.Conn, envelopeID string, payload interface{}) error {
+	return websocket.JSON.Send(conn, &Ack{EnvelopeID: envelopeID, Payload: payload})
+}
@@ -0,0 +1,67 @@
+package socketmode
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/net/websocket"
+)
+
+// testServer sends a hello and an events envelope and echoes the ack back
+// as the envelope_id of a disconnect envelope.
+func testServer(ws *websocket.Conn) {
+	defer ws.Close()
+
+	websocket.Message.Send(ws, `{"type": "hello", "num_connections": 1}`)
+	websocket.Message.Send(ws, `{"type": "events_api", "envelope_id": "env-1", "accepts_response_payload": false, "payload": {"type": "event_callback", "event": {"type": "message"}}}`)
+
+	ack := &Ack{}
+	if err := websocket.JSON.Receive(ws, ack); err != nil {
+		return
+	}
+
+	websocket.JSON.Send(ws, &Envelope{Type: DISCONNECT, Reason: "refresh_requested", EnvelopeID: ack.EnvelopeID})
+}
+
+func TestReceiveAndAck(t *testing.T) {
+	server := httptest.NewServer(websocket.Handler(testServer))
+	defer server.Close()
+
+	client := NewClient()
+	conn, err := client.Connect("ws://" + server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("webSocket connection error %#v", err)
+	}
+	defer conn.Close()
+
+	hello, err := client.ReceiveEnvelope(conn)
+	if err != nil {
+		t.Fatalf("error on hello receive %#v", err)
+	}
+	if hello.Type != HELLO || hello.NumConnections != 1 {
+		t.Errorf("unexpected hello envelope %#v", hello)
+	}
+
+	envelope, err := client.ReceiveEnvelope(conn)
+	if err != nil {
+		t.Fatalf("error on envelope receive %#v", err)
+	}
+	if envelope.Type != EVENTS || envelope.EnvelopeID != "env-1" || len(envelope.Payload) == 0 {
+		t.Errorf("unexpected events envelope %#v", envelope)
+	}
+
+	if err := client.Ack(conn, envelope.EnvelopeID, nil); err != nil {
+		t.Fatalf("error on ack %#v", err)
+	}
+
+	disconnect, err := client.ReceiveEnvelope(conn)
+	if err != nil {
+		t.Fatalf("error on disconnect receive %#v", err)
+	}
+	if disconnect.Type != DISCONNECT || disconnect.Reason != "refresh_requested" {
+		t.Errorf("unexpected disconnect envelope %#v", disconnect)
+	}
+	if disconnect.EnvelopeID != "env-1" {
+		t.Errorf("server received wrong ack %q", disconnect.EnvelopeID)
+	}
+}
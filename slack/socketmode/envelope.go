@@ -0,0 +1,42 @@
+package socketmode
+
+import "encoding/json"
+
+// EnvelopeType is the type of a message sent over a Socket Mode connection.
+type EnvelopeType string
+
+const (
+	// HELLO is sent when the connection is established
+	HELLO = "hello"
+	// DISCONNECT is sent before slack closes the connection
+	DISCONNECT = "disconnect"
+	// EVENTS is an envelope with an Events API payload
+	EVENTS = "events_api"
+	// SLASHCOMMANDS is an envelope with a slash command payload
+	SLASHCOMMANDS = "slash_commands"
+	// INTERACTIVE is an envelope with an interactivity payload
+	INTERACTIVE = "interactive"
+)
+
+// Envelope wraps every message sent by slack over a Socket Mode connection.
+type Envelope struct {
+	Type                   EnvelopeType    `json:"type"`
+	EnvelopeID             string          `json:"envelope_id,omitempty"`
+	Payload                json.RawMessage `json:"payload,omitempty"`
+	AcceptsResponsePayload bool            `json:"accepts_response_payload,omitempty"`
+	RetryAttempt           int             `json:"retry_attempt,omitempty"`
+	RetryReason            string          `json:"retry_reason,omitempty"`
+
+	// set on hello envelopes
+	NumConnections int `json:"num_connections,omitempty"`
+
+	// set on disconnect envelopes
+	Reason string `json:"reason,omitempty"`
+}
+
+// Ack acknowledges an envelope, slack redelivers envelopes which are not
+// acknowledged within 3 seconds.
+type Ack struct {
+	EnvelopeID string      `json:"envelope_id"`
+	Payload    interface{} `json:"payload,omitempty"`
+}
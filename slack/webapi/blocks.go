@@ -0,0 +1,724 @@
+package webapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Maximum number of blocks slack accepts
+const (
+	MaxMessageBlocks = 50
+	MaxViewBlocks    = 100
+)
+
+// Text object types
+const (
+	PlainText = "plain_text"
+	Markdown  = "mrkdwn"
+)
+
+// Block is a Block Kit layout block
+// ex. https://api.slack.com/reference/block-kit/blocks
+type Block interface {
+	BlockType() string
+	Validate() error
+}
+
+// Element is a block element, or a text object within a context block
+// ex. https://api.slack.com/reference/block-kit/block-elements
+type Element interface {
+	ElementType() string
+	Validate() error
+}
+
+// ValidationError is returned for blocks which are rejected by slack
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+// NewValidationError creates new ValidationError
+func NewValidationError(field, reason string, args ...interface{}) *ValidationError {
+	return &ValidationError{Field: field, Reason: fmt.Sprintf(reason, args...)}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+// within prefixes the field of a validation error with the enclosing field
+func within(field string, err error) error {
+	if verr, ok := err.(*ValidationError); ok {
+		return NewValidationError(field+"."+verr.Field, "%s", verr.Reason)
+	}
+
+	return err
+}
+
+// ValidateBlocks validates every block and the number of blocks
+func ValidateBlocks(blocks []Block, max int) error {
+	if len(blocks) > max {
+		return NewValidationError("blocks", "must not contain more than %d blocks", max)
+	}
+
+	for i, block := range blocks {
+		if block == nil {
+			return NewValidationError(fmt.Sprintf("blocks[%d]", i), "must not be nil")
+		}
+
+		if err := block.Validate(); err != nil {
+			return within(fmt.Sprintf("blocks[%d]", i), err)
+		}
+	}
+
+	return nil
+}
+
+func validateLength(field, value string, max int) error {
+	if utf8.RuneCountInString(value) > max {
+		return NewValidationError(field, "must not exceed %d characters", max)
+	}
+
+	return nil
+}
+
+func validateRequired(field, value string, max int) error {
+	if value == "" {
+		return NewValidationError(field, "is required")
+	}
+
+	return validateLength(field, value, max)
+}
+
+func validateText(field string, text *TextObject, plain bool, max int) error {
+	if text == nil {
+		return NewValidationError(field, "is required")
+	}
+
+	if plain && text.Type != PlainText {
+		return NewValidationError(field, "must be a %s text", PlainText)
+	}
+
+	return within(field, text.validate(max))
+}
+
+// marshalTyped encodes v with the given type field
+func marshalTyped(typ string, v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	head := `{"type":` + strconv.Quote(typ)
+	if len(data) <= 2 {
+		return []byte(head + "}"), nil
+	}
+
+	return append([]byte(head+","), data[1:]...), nil
+}
+
+// TextObject is a plain_text or mrkdwn text
+type TextObject struct {
+	Type     string `json:"type"`
+	Text     string `json:"text"`
+	Emoji    bool   `json:"emoji,omitempty"`
+	Verbatim bool   `json:"verbatim,omitempty"`
+}
+
+// NewPlainText creates new plain_text TextObject
+func NewPlainText(text string) *TextObject {
+	return &TextObject{Type: PlainText, Text: text}
+}
+
+// NewMarkdown creates new mrkdwn TextObject
+func NewMarkdown(text string) *TextObject {
+	return &TextObject{Type: Markdown, Text: text}
+}
+
+// ElementType returns the type of the text
+func (t *TextObject) ElementType() string {
+	return t.Type
+}
+
+// Validate validates the text
+func (t *TextObject) Validate() error {
+	return t.validate(3000)
+}
+
+func (t *TextObject) validate(max int) error {
+	if t.Type != PlainText && t.Type != Markdown {
+		return NewValidationError("type", "must be %s or %s", PlainText, Markdown)
+	}
+
+	return validateRequired("text", t.Text, max)
+}
+
+// OptionObject is an option of a select menu
+type OptionObject struct {
+	Text  *TextObject `json:"text"`
+	Value string      `json:"value"`
+}
+
+// NewOption creates new OptionObject
+func NewOption(text, value string) *OptionObject {
+	return &OptionObject{Text: NewPlainText(text), Value: value}
+}
+
+func (o *OptionObject) validate() error {
+	if err := validateText("text", o.Text, true, 75); err != nil {
+		return err
+	}
+
+	return validateRequired("value", o.Value, 150)
+}
+
+// SectionBlock displays text, fields and an optional accessory element
+type SectionBlock struct {
+	Text      *TextObject   `json:"text,omitempty"`
+	BlockID   string        `json:"block_id,omitempty"`
+	Fields    []*TextObject `json:"fields,omitempty"`
+	Accessory Element       `json:"accessory,omitempty"`
+}
+
+// NewSectionBlock creates new SectionBlock
+func NewSectionBlock(text *TextObject, fields ...*TextObject) *SectionBlock {
+	return &SectionBlock{Text: text, Fields: fields}
+}
+
+// BlockType returns section
+func (b *SectionBlock) BlockType() string {
+	return "section"
+}
+
+// MarshalJSON encodes the block with its type
+func (b *SectionBlock) MarshalJSON() ([]byte, error) {
+	type section SectionBlock
+	return marshalTyped(b.BlockType(), (*section)(b))
+}
+
+// Validate validates the block against slack's limits
+func (b *SectionBlock) Validate() error {
+	if b.Text == nil && len(b.Fields) == 0 {
+		return NewValidationError("text", "is required without fields")
+	}
+
+	if b.Text != nil {
+		if err := within("text", b.Text.validate(3000)); err != nil {
+			return err
+		}
+	}
+
+	if len(b.Fields) > 10 {
+		return NewValidationError("fields", "must not contain more than 10 fields")
+	}
+
+	for i, field := range b.Fields {
+		if err := validateText(fmt.Sprintf("fields[%d]", i), field, false, 2000); err != nil {
+			return err
+		}
+	}
+
+	if b.Accessory != nil {
+		if !contains([]string{"button", "static_select", "image"}, b.Accessory.ElementType()) {
+			return NewValidationError("accessory", "%s is not allowed", b.Accessory.ElementType())
+		}
+
+		if err := within("accessory", b.Accessory.Validate()); err != nil {
+			return err
+		}
+	}
+
+	return validateLength("block_id", b.BlockID, 255)
+}
+
+// DividerBlock separates blocks
+type DividerBlock struct {
+	BlockID string `json:"block_id,omitempty"`
+}
+
+// NewDividerBlock creates new DividerBlock
+func NewDividerBlock() *DividerBlock {
+	return &DividerBlock{}
+}
+
+// BlockType returns divider
+func (b *DividerBlock) BlockType() string {
+	return "divider"
+}
+
+// MarshalJSON encodes the block with its type
+func (b *DividerBlock) MarshalJSON() ([]byte, error) {
+	type divider DividerBlock
+	return marshalTyped(b.BlockType(), (*divider)(b))
+}
+
+// Validate validates the block against slack's limits
+func (b *DividerBlock) Validate() error {
+	return validateLength("block_id", b.BlockID, 255)
+}
+
+// HeaderBlock displays a larger plain text
+type HeaderBlock struct {
+	Text    *TextObject `json:"text"`
+	BlockID string      `json:"block_id,omitempty"`
+}
+
+// NewHeaderBlock creates new HeaderBlock
+func NewHeaderBlock(text string) *HeaderBlock {
+	return &HeaderBlock{Text: NewPlainText(text)}
+}
+
+// BlockType returns header
+func (b *HeaderBlock) BlockType() string {
+	return "header"
+}
+
+// MarshalJSON encodes the block with its type
+func (b *HeaderBlock) MarshalJSON() ([]byte, error) {
+	type header HeaderBlock
+	return marshalTyped(b.BlockType(), (*header)(b))
+}
+
+// Validate validates the block against slack's limits
+func (b *HeaderBlock) Validate() error {
+	if err := validateText("text", b.Text, true, 150); err != nil {
+		return err
+	}
+
+	return validateLength("block_id", b.BlockID, 255)
+}
+
+// ContextBlock displays small texts and images
+type ContextBlock struct {
+	Elements []Element `json:"elements"`
+	BlockID  string    `json:"block_id,omitempty"`
+}
+
+// NewContextBlock creates new ContextBlock of text objects and image elements
+func NewContextBlock(elements ...Element) *ContextBlock {
+	return &ContextBlock{Elements: elements}
+}
+
+// BlockType returns context
+func (b *ContextBlock) BlockType() string {
+	return "context"
+}
+
+// MarshalJSON encodes the block with its type
+func (b *ContextBlock) MarshalJSON() ([]byte, error) {
+	type context ContextBlock
+	return marshalTyped(b.BlockType(), (*context)(b))
+}
+
+// Validate validates the block against slack's limits
+func (b *ContextBlock) Validate() error {
+	if err := validateElements(b.Elements, 10, PlainText, Markdown, "image"); err != nil {
+		return err
+	}
+
+	return validateLength("block_id", b.BlockID, 255)
+}
+
+// ActionsBlock holds interactive elements
+type ActionsBlock struct {
+	Elements []Element `json:"elements"`
+	BlockID  string    `json:"block_id,omitempty"`
+}
+
+// NewActionsBlock creates new ActionsBlock
+func NewActionsBlock(elements ...Element) *ActionsBlock {
+	return &ActionsBlock{Elements: elements}
+}
+
+// BlockType returns actions
+func (b *ActionsBlock) BlockType() string {
+	return "actions"
+}
+
+// MarshalJSON encodes the block with its type
+func (b *ActionsBlock) MarshalJSON() ([]byte, error) {
+	type actions ActionsBlock
+	return marshalTyped(b.BlockType(), (*actions)(b))
+}
+
+// Validate validates the block against slack's limits
+func (b *ActionsBlock) Validate() error {
+	if err := validateElements(b.Elements, 25, "button", "static_select"); err != nil {
+		return err
+	}
+
+	return validateLength("block_id", b.BlockID, 255)
+}
+
+func validateElements(elements []Element, max int, allowed ...string) error {
+	if len(elements) == 0 {
+		return NewValidationError("elements", "is required")
+	}
+
+	if len(elements) > max {
+		return NewValidationError("elements", "must not contain more than %d elements", max)
+	}
+
+	for i, element := range elements {
+		field := fmt.Sprintf("elements[%d]", i)
+		if element == nil {
+			return NewValidationError(field, "must not be nil")
+		}
+
+		if !contains(allowed, element.ElementType()) {
+			return NewValidationError(field, "%s is not allowed", element.ElementType())
+		}
+
+		if err := element.Validate(); err != nil {
+			return within(field, err)
+		}
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// InputBlock collects information from users in a modal
+type InputBlock struct {
+	Label          *TextObject `json:"label"`
+	Element        Element     `json:"element"`
+	BlockID        string      `json:"block_id,omitempty"`
+	Hint           *TextObject `json:"hint,omitempty"`
+	Optional       bool        `json:"optional,omitempty"`
+	DispatchAction bool        `json:"dispatch_action,omitempty"`
+}
+
+// NewInputBlock creates new InputBlock
+func NewInputBlock(blockID, label string, element Element) *InputBlock {
+	return &InputBlock{BlockID: blockID, Label: NewPlainText(label), Element: element}
+}
+
+// BlockType returns input
+func (b *InputBlock) BlockType() string {
+	return "input"
+}
+
+// MarshalJSON encodes the block with its type
+func (b *InputBlock) MarshalJSON() ([]byte, error) {
+	type input InputBlock
+	return marshalTyped(b.BlockType(), (*input)(b))
+}
+
+// Validate validates the block against slack's limits
+func (b *InputBlock) Validate() error {
+	if err := validateText("label", b.Label, true, 2000); err != nil {
+		return err
+	}
+
+	if b.Element == nil {
+		return NewValidationError("element", "is required")
+	}
+
+	if !contains([]string{"plain_text_input", "static_select"}, b.Element.ElementType()) {
+		return NewValidationError("element", "%s is not allowed", b.Element.ElementType())
+	}
+
+	if err := within("element", b.Element.Validate()); err != nil {
+		return err
+	}
+
+	if b.Hint != nil {
+		if err := validateText("hint", b.Hint, true, 2000); err != nil {
+			return err
+		}
+	}
+
+	return validateLength("block_id", b.BlockID, 255)
+}
+
+// ImageBlock displays an image
+type ImageBlock struct {
+	ImageURL string      `json:"image_url"`
+	AltText  string      `json:"alt_text"`
+	Title    *TextObject `json:"title,omitempty"`
+	BlockID  string      `json:"block_id,omitempty"`
+}
+
+// NewImageBlock creates new ImageBlock
+func NewImageBlock(imageURL, altText string) *ImageBlock {
+	return &ImageBlock{ImageURL: imageURL, AltText: altText}
+}
+
+// BlockType returns image
+func (b *ImageBlock) BlockType() string {
+	return "image"
+}
+
+// MarshalJSON encodes the block with its type
+func (b *ImageBlock) MarshalJSON() ([]byte, error) {
+	type image ImageBlock
+	return marshalTyped(b.BlockType(), (*image)(b))
+}
+
+// Validate validates the block against slack's limits
+func (b *ImageBlock) Validate() error {
+	if err := validateRequired("image_url", b.ImageURL, 3000); err != nil {
+		return err
+	}
+
+	if err := validateRequired("alt_text", b.AltText, 2000); err != nil {
+		return err
+	}
+
+	if b.Title != nil {
+		if err := validateText("title", b.Title, true, 2000); err != nil {
+			return err
+		}
+	}
+
+	return validateLength("block_id", b.BlockID, 255)
+}
+
+// RawBlock is a block decoded from a slack response, kept as JSON
+type RawBlock struct {
+	Type string
+	JSON json.RawMessage
+}
+
+// BlockType returns the type of the block
+func (b *RawBlock) BlockType() string {
+	return b.Type
+}
+
+// MarshalJSON returns the block as received
+func (b *RawBlock) MarshalJSON() ([]byte, error) {
+	return b.JSON, nil
+}
+
+// Validate is left to slack for raw blocks
+func (b *RawBlock) Validate() error {
+	return nil
+}
+
+// Blocks is a list of blocks which can be decoded from slack responses
+type Blocks []Block
+
+// MarshalJSON encodes the blocks, slack expects an empty list instead of null
+func (b Blocks) MarshalJSON() ([]byte, error) {
+	if b == nil {
+		return []byte("[]"), nil
+	}
+
+	return json.Marshal([]Block(b))
+}
+
+// UnmarshalJSON decodes the blocks as RawBlock
+func (b *Blocks) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	blocks := make(Blocks, 0, len(raw))
+	for _, block := range raw {
+		typed := struct {
+			Type string `json:"type"`
+		}{}
+		if err := json.Unmarshal(block, &typed); err != nil {
+			return err
+		}
+
+		blocks = append(blocks, &RawBlock{Type: typed.Type, JSON: block})
+	}
+
+	*b = blocks
+	return nil
+}
+
+// ButtonElement is an interactive button
+type ButtonElement struct {
+	Text     *TextObject `json:"text"`
+	ActionID string      `json:"action_id"`
+	Value    string      `json:"value,omitempty"`
+	URL      string      `json:"url,omitempty"`
+	Style    string      `json:"style,omitempty"`
+}
+
+// NewButton creates new ButtonElement
+func NewButton(actionID, text, value string) *ButtonElement {
+	return &ButtonElement{ActionID: actionID, Text: NewPlainText(text), Value: value}
+}
+
+// ElementType returns button
+func (e *ButtonElement) ElementType() string {
+	return "button"
+}
+
+// MarshalJSON encodes the element with its type
+func (e *ButtonElement) MarshalJSON() ([]byte, error) {
+	type button ButtonElement
+	return marshalTyped(e.ElementType(), (*button)(e))
+}
+
+// Validate validates the element against slack's limits
+func (e *ButtonElement) Validate() error {
+	if err := validateText("text", e.Text, true, 75); err != nil {
+		return err
+	}
+
+	if err := validateRequired("action_id", e.ActionID, 255); err != nil {
+		return err
+	}
+
+	if err := validateLength("value", e.Value, 2000); err != nil {
+		return err
+	}
+
+	if err := validateLength("url", e.URL, 3000); err != nil {
+		return err
+	}
+
+	if e.Style != "" && e.Style != "primary" && e.Style != "danger" {
+		return NewValidationError("style", "must be primary or danger")
+	}
+
+	return nil
+}
+
+// StaticSelectElement is a select menu with static options
+type StaticSelectElement struct {
+	Placeholder   *TextObject     `json:"placeholder"`
+	ActionID      string          `json:"action_id"`
+	Options       []*OptionObject `json:"options"`
+	InitialOption *OptionObject   `json:"initial_option,omitempty"`
+}
+
+// NewStaticSelect creates new StaticSelectElement
+func NewStaticSelect(actionID, placeholder string, options ...*OptionObject) *StaticSelectElement {
+	return &StaticSelectElement{ActionID: actionID, Placeholder: NewPlainText(placeholder), Options: options}
+}
+
+// ElementType returns static_select
+func (e *StaticSelectElement) ElementType() string {
+	return "static_select"
+}
+
+// MarshalJSON encodes the element with its type
+func (e *StaticSelectElement) MarshalJSON() ([]byte, error) {
+	type staticSelect StaticSelectElement
+	return marshalTyped(e.ElementType(), (*staticSelect)(e))
+}
+
+// Validate validates the element against slack's limits
+func (e *StaticSelectElement) Validate() error {
+	if err := validateText("placeholder", e.Placeholder, true, 150); err != nil {
+		return err
+	}
+
+	if err := validateRequired("action_id", e.ActionID, 255); err != nil {
+		return err
+	}
+
+	if len(e.Options) == 0 || len(e.Options) > 100 {
+		return NewValidationError("options", "must contain between 1 and 100 options")
+	}
+
+	for i, option := range e.Options {
+		field := fmt.Sprintf("options[%d]", i)
+		if option == nil {
+			return NewValidationError(field, "must not be nil")
+		}
+
+		if err := within(field, option.validate()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PlainTextInputElement is a text input of an input block
+type PlainTextInputElement struct {
+	ActionID     string      `json:"action_id"`
+	Placeholder  *TextObject `json:"placeholder,omitempty"`
+	InitialValue string      `json:"initial_value,omitempty"`
+	Multiline    bool        `json:"multiline,omitempty"`
+	MinLength    int         `json:"min_length,omitempty"`
+	MaxLength    int         `json:"max_length,omitempty"`
+}
+
+// NewPlainTextInput creates new PlainTextInputElement
+func NewPlainTextInput(actionID string) *PlainTextInputElement {
+	return &PlainTextInputElement{ActionID: actionID}
+}
+
+// ElementType returns plain_text_input
+func (e *PlainTextInputElement) ElementType() string {
+	return "plain_text_input"
+}
+
+// MarshalJSON encodes the element with its type
+func (e *PlainTextInputElement) MarshalJSON() ([]byte, error) {
+	type input PlainTextInputElement
+	return marshalTyped(e.ElementType(), (*input)(e))
+}
+
+// Validate validates the element against slack's limits
+func (e *PlainTextInputElement) Validate() error {
+	if err := validateRequired("action_id", e.ActionID, 255); err != nil {
+		return err
+	}
+
+	if e.Placeholder != nil {
+		if err := validateText("placeholder", e.Placeholder, true, 150); err != nil {
+			return err
+		}
+	}
+
+	if e.MinLength < 0 || e.MinLength > 3000 {
+		return NewValidationError("min_length", "must be between 0 and 3000")
+	}
+
+	if e.MaxLength < 0 || (e.MaxLength > 0 && e.MaxLength < e.MinLength) {
+		return NewValidationError("max_length", "must not be less than min_length")
+	}
+
+	return nil
+}
+
+// ImageElement is an image within a section or a context block
+type ImageElement struct {
+	ImageURL string `json:"image_url"`
+	AltText  string `json:"alt_text"`
+}
+
+// NewImageElement creates new ImageElement
+func NewImageElement(imageURL, altText string) *ImageElement {
+	return &ImageElement{ImageURL: imageURL, AltText: altText}
+}
+
+// ElementType returns image
+func (e *ImageElement) ElementType() string {
+	return "image"
+}
+
+// MarshalJSON encodes the element with its type
+func (e *ImageElement) MarshalJSON() ([]byte, error) {
+	type image ImageElement
+	return marshalTyped(e.ElementType(), (*image)(e))
+}
+
+// Validate validates the element against slack's limits
+func (e *ImageElement) Validate() error {
+	if err := validateRequired("image_url", e.ImageURL, 3000); err != nil {
+		return err
+	}
+
+	return validateRequired("alt_text", e.AltText, 2000)
+}
@@ -0,0 +1,141 @@
+package webapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestBlocksMarshal(t *testing.T) {
+	section := NewSectionBlock(NewMarkdown("*Deploy* api?"))
+	section.Accessory = NewButton("approve", "Approve", "release-1")
+
+	blocks := []Block{
+		NewHeaderBlock("Release"),
+		section,
+		NewDividerBlock(),
+		NewContextBlock(NewPlainText("requested by kochev")),
+	}
+
+	encoded, err := json.Marshal(blocks)
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	expected := `[` +
+		`{"type":"header","text":{"type":"plain_text","text":"Release"}},` +
+		`{"type":"section","text":{"type":"mrkdwn","text":"*Deploy* api?"},` +
+		`"accessory":{"type":"button","text":{"type":"plain_text","text":"Approve"},"action_id":"approve","value":"release-1"}},` +
+		`{"type":"divider"},` +
+		`{"type":"context","elements":[{"type":"plain_text","text":"requested by kochev"}]}` +
+		`]`
+	if string(encoded) != expected {
+		t.Errorf("unexpected blocks\n%s\n%s", encoded, expected)
+	}
+
+	if err := ValidateBlocks(blocks, MaxMessageBlocks); err != nil {
+		t.Errorf("unexpected validation error %#v", err)
+	}
+}
+
+func TestBlocksValidation(t *testing.T) {
+	tests := []struct {
+		block Block
+		field string
+	}{
+		{NewSectionBlock(nil), "blocks[0].text"},
+		{NewSectionBlock(NewMarkdown(strings.Repeat("a", 3001))), "blocks[0].text.text"},
+		{NewHeaderBlock(strings.Repeat("a", 151)), "blocks[0].text.text"},
+		{&HeaderBlock{Text: NewMarkdown("header")}, "blocks[0].text"},
+		{NewActionsBlock(), "blocks[0].elements"},
+		{NewActionsBlock(NewPlainTextInput("name")), "blocks[0].elements[0]"},
+		{NewActionsBlock(NewButton("", "Approve", "")), "blocks[0].elements[0].action_id"},
+		{NewActionsBlock(NewButton("approve", strings.Repeat("a", 76), "")), "blocks[0].elements[0].text.text"},
+		{NewActionsBlock(NewStaticSelect("env", "Environment")), "blocks[0].elements[0].options"},
+		{NewInputBlock("service", "Service", NewButton("approve", "Approve", "")), "blocks[0].element"},
+		{NewImageBlock("https://example.com/a.png", ""), "blocks[0].alt_text"},
+		{&DividerBlock{BlockID: strings.Repeat("a", 256)}, "blocks[0].block_id"},
+	}
+
+	for _, test := range tests {
+		err := ValidateBlocks([]Block{test.block}, MaxMessageBlocks)
+		verr, ok := err.(*ValidationError)
+		if !ok {
+			t.Errorf("expected validation error for %#v, got %#v", test.block, err)
+			continue
+		}
+
+		if verr.Field != test.field {
+			t.Errorf("expected error of %s, got %s", test.field, verr)
+		}
+	}
+
+	blocks := make([]Block, MaxMessageBlocks+1)
+	for i := range blocks {
+		blocks[i] = NewDividerBlock()
+	}
+	if err := ValidateBlocks(blocks, MaxMessageBlocks); err == nil {
+		t.Error("expected error for too many blocks")
+	}
+}
+
+func TestViewBlocksDecode(t *testing.T) {
+	view := &View{}
+	err := json.Unmarshal([]byte(`{"type":"modal","blocks":[{"type":"divider","block_id":"d1"}]}`), view)
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if len(view.Blocks) != 1 || view.Blocks[0].BlockType() != "divider" {
+		t.Fatalf("unexpected blocks %#v", view.Blocks)
+	}
+
+	encoded, _ := json.Marshal(view.Blocks)
+	if string(encoded) != `[{"type":"divider","block_id":"d1"}]` {
+		t.Errorf("unexpected encoded blocks %s", encoded)
+	}
+
+	encoded, _ = json.Marshal(NewModal("deploy", "Deploy"))
+	if !strings.Contains(string(encoded), `"blocks":[]`) {
+		t.Errorf("expected empty blocks list %s", encoded)
+	}
+}
+
+func TestPostMessageBlocks(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var blocks string
+	httpmock.RegisterResponder("POST", "https://slack.com/api/chat.postMessage", func(req *http.Request) (*http.Response, error) {
+		if err := req.ParseForm(); err != nil {
+			return nil, err
+		}
+		blocks = req.PostForm.Get("blocks")
+
+		return httpmock.NewJsonResponse(200, &PostMessageResponse{APIResponse: APIResponse{OK: true}})
+	})
+
+	client := NewClient("123")
+
+	post := NewPostMessage("channel", "fallback")
+	post.Blocks = []Block{NewDividerBlock()}
+	if _, err := client.PostMessage(post); err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if blocks != `[{"type":"divider"}]` {
+		t.Errorf("unexpected blocks %q", blocks)
+	}
+
+	post.Blocks = []Block{NewHeaderBlock("")}
+	if _, err := client.PostMessage(post); err == nil {
+		t.Error("expected invalid blocks to be rejected")
+	}
+
+	if info := httpmock.GetCallCountInfo(); info["POST https://slack.com/api/chat.postMessage"] != 1 {
+		t.Errorf("invalid blocks were posted %#v", info)
+	}
+}
@@ -0,0 +1,303 @@
+package webapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// UpdateMessage replaces the text or the blocks of a message
+type UpdateMessage struct {
+	Channel        string
+	TimeStamp      string
+	Text           string
+	Blocks         []Block
+	Attachments    []*MessageAttachment
+	Parse          string
+	LinkNames      bool
+	ReplyBroadcast bool
+}
+
+// NewUpdateMessage creates new UpdateMessage
+func NewUpdateMessage(channel, timeStamp, text string) *UpdateMessage {
+	return &UpdateMessage{Channel: channel, TimeStamp: timeStamp, Text: text}
+}
+
+// ToURLValues method
+func (message *UpdateMessage) ToURLValues() url.Values {
+	values := url.Values{}
+	values.Add("channel", message.Channel)
+	values.Add("ts", message.TimeStamp)
+	values.Add("text", message.Text)
+	if message.Parse != "" {
+		values.Add("parse", message.Parse)
+	}
+	if message.LinkNames {
+		values.Add("link_names", "true")
+	}
+	if message.ReplyBroadcast {
+		values.Add("reply_broadcast", "true")
+	}
+	addJSON(values, "attachments", message.Attachments, len(message.Attachments) > 0)
+	addJSON(values, "blocks", message.Blocks, len(message.Blocks) > 0)
+
+	return values
+}
+
+// UpdateMessageResponse is returned by chat.update
+type UpdateMessageResponse struct {
+	APIResponse
+	Channel   string `json:"channel"`
+	TimeStamp string `json:"ts"`
+	Text      string `json:"text"`
+}
+
+// ChatUpdate updates a message
+func (c *Client) ChatUpdate(message *UpdateMessage) (*UpdateMessageResponse, error) {
+	return c.ChatUpdateContext(context.Background(), message)
+}
+
+// ChatUpdateContext is ChatUpdate with a context
+func (c *Client) ChatUpdateContext(ctx context.Context, message *UpdateMessage) (*UpdateMessageResponse, error) {
+	if err := ValidateBlocks(message.Blocks, MaxMessageBlocks); err != nil {
+		return nil, err
+	}
+
+	response := &UpdateMessageResponse{}
+	if err := c.PostContext(ctx, "chat.update", message.ToURLValues(), &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// DeleteMessageResponse is returned by chat.delete
+type DeleteMessageResponse struct {
+	APIResponse
+	Channel   string `json:"channel"`
+	TimeStamp string `json:"ts"`
+}
+
+// ChatDelete deletes a message
+func (c *Client) ChatDelete(channel, timeStamp string) (*DeleteMessageResponse, error) {
+	return c.ChatDeleteContext(context.Background(), channel, timeStamp)
+}
+
+// ChatDeleteContext is ChatDelete with a context
+func (c *Client) ChatDeleteContext(ctx context.Context, channel, timeStamp string) (*DeleteMessageResponse, error) {
+	body := url.Values{}
+	body.Add("channel", channel)
+	body.Add("ts", timeStamp)
+
+	response := &DeleteMessageResponse{}
+	if err := c.PostContext(ctx, "chat.delete", body, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// EphemeralMessage is only visible to the given user
+type EphemeralMessage struct {
+	Channel         string
+	User            string
+	Text            string
+	Blocks          []Block
+	Attachments     []*MessageAttachment
+	ThreadTimeStamp string
+	AsUser          bool
+	LinkNames       bool
+}
+
+// NewEphemeralMessage creates new EphemeralMessage
+func NewEphemeralMessage(channel, user, text string) *EphemeralMessage {
+	return &EphemeralMessage{Channel: channel, User: user, Text: text}
+}
+
+// ToURLValues method
+func (message *EphemeralMessage) ToURLValues() url.Values {
+	values := url.Values{}
+	values.Add("channel", message.Channel)
+	values.Add("user", message.User)
+	values.Add("text", message.Text)
+	if message.ThreadTimeStamp != "" {
+		values.Add("thread_ts", message.ThreadTimeStamp)
+	}
+	if message.AsUser {
+		values.Add("as_user", "true")
+	}
+	if message.LinkNames {
+		values.Add("link_names", "true")
+	}
+	addJSON(values, "attachments", message.Attachments, len(message.Attachments) > 0)
+	addJSON(values, "blocks", message.Blocks, len(message.Blocks) > 0)
+
+	return values
+}
+
+// EphemeralMessageResponse is returned by chat.postEphemeral
+type EphemeralMessageResponse struct {
+	APIResponse
+	MessageTimeStamp string `json:"message_ts"`
+}
+
+// ChatPostEphemeral sends a message only visible to the user
+func (c *Client) ChatPostEphemeral(message *EphemeralMessage) (*EphemeralMessageResponse, error) {
+	return c.ChatPostEphemeralContext(context.Background(), message)
+}
+
+// ChatPostEphemeralContext is ChatPostEphemeral with a context
+func (c *Client) ChatPostEphemeralContext(ctx context.Context, message *EphemeralMessage) (*EphemeralMessageResponse, error) {
+	if err := ValidateBlocks(message.Blocks, MaxMessageBlocks); err != nil {
+		return nil, err
+	}
+
+	response := &EphemeralMessageResponse{}
+	if err := c.PostContext(ctx, "chat.postEphemeral", message.ToURLValues(), &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// ScheduledMessage is posted by slack at the given time
+type ScheduledMessage struct {
+	Channel         string
+	PostAt          time.Time
+	Text            string
+	Blocks          []Block
+	Attachments     []*MessageAttachment
+	ThreadTimeStamp string
+	ReplyBroadcast  bool
+	LinkNames       bool
+}
+
+// NewScheduledMessage creates new ScheduledMessage
+func NewScheduledMessage(channel string, postAt time.Time, text string) *ScheduledMessage {
+	return &ScheduledMessage{Channel: channel, PostAt: postAt, Text: text}
+}
+
+// ToURLValues method
+func (message *ScheduledMessage) ToURLValues() url.Values {
+	values := url.Values{}
+	values.Add("channel", message.Channel)
+	values.Add("post_at", strconv.FormatInt(message.PostAt.Unix(), 10))
+	values.Add("text", message.Text)
+	if message.ThreadTimeStamp != "" {
+		values.Add("thread_ts", message.ThreadTimeStamp)
+		values.Add("reply_broadcast", strconv.FormatBool(message.ReplyBroadcast))
+	}
+	if message.LinkNames {
+		values.Add("link_names", "true")
+	}
+	addJSON(values, "attachments", message.Attachments, len(message.Attachments) > 0)
+	addJSON(values, "blocks", message.Blocks, len(message.Blocks) > 0)
+
+	return values
+}
+
+// ScheduledMessageResponse is returned by chat.scheduleMessage
+type ScheduledMessageResponse struct {
+	APIResponse
+	Channel            string   `json:"channel"`
+	ScheduledMessageID string   `json:"scheduled_message_id"`
+	PostAt             int64    `json:"post_at"`
+	Message            *Message `json:"message,omitempty"`
+}
+
+// ChatScheduleMessage schedules a message
+func (c *Client) ChatScheduleMessage(message *ScheduledMessage) (*ScheduledMessageResponse, error) {
+	return c.ChatScheduleMessageContext(context.Background(), message)
+}
+
+// ChatScheduleMessageContext is ChatScheduleMessage with a context
+func (c *Client) ChatScheduleMessageContext(ctx context.Context, message *ScheduledMessage) (*ScheduledMessageResponse, error) {
+	if err := ValidateBlocks(message.Blocks, MaxMessageBlocks); err != nil {
+		return nil, err
+	}
+
+	response := &ScheduledMessageResponse{}
+	if err := c.PostContext(ctx, "chat.scheduleMessage", message.ToURLValues(), &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// ChatDeleteScheduledMessage deletes a scheduled message before it is posted
+func (c *Client) ChatDeleteScheduledMessage(channel, scheduledMessageID string) error {
+	return c.ChatDeleteScheduledMessageContext(context.Background(), channel, scheduledMessageID)
+}
+
+// ChatDeleteScheduledMessageContext is ChatDeleteScheduledMessage with a context
+func (c *Client) ChatDeleteScheduledMessageContext(ctx context.Context, channel, scheduledMessageID string) error {
+	body := url.Values{}
+	body.Add("channel", channel)
+	body.Add("scheduled_message_id", scheduledMessageID)
+
+	return c.PostContext(ctx, "chat.deleteScheduledMessage", body, &APIResponse{})
+}
+
+// PermalinkResponse is returned by chat.getPermalink
+type PermalinkResponse struct {
+	APIResponse
+	Channel   string `json:"channel"`
+	Permalink string `json:"permalink"`
+}
+
+// ChatGetPermalink returns the permanent URL of a message
+func (c *Client) ChatGetPermalink(channel, timeStamp string) (string, error) {
+	return c.ChatGetPermalinkContext(context.Background(), channel, timeStamp)
+}
+
+// ChatGetPermalinkContext is ChatGetPermalink with a context
+func (c *Client) ChatGetPermalinkContext(ctx context.Context, channel, timeStamp string) (string, error) {
+	query := url.Values{}
+	query.Add("channel", channel)
+	query.Add("message_ts", timeStamp)
+
+	response := &PermalinkResponse{}
+	if err := c.GetContext(ctx, "chat.getPermalink", &query, &response); err != nil {
+		return "", err
+	}
+
+	return response.Permalink, nil
+}
+
+// MeMessageResponse is returned by chat.meMessage
+type MeMessageResponse struct {
+	APIResponse
+	Channel   string `json:"channel"`
+	TimeStamp string `json:"ts"`
+}
+
+// ChatMeMessage sends a /me message to the channel
+func (c *Client) ChatMeMessage(channel, text string) (*MeMessageResponse, error) {
+	return c.ChatMeMessageContext(context.Background(), channel, text)
+}
+
+// ChatMeMessageContext is ChatMeMessage with a context
+func (c *Client) ChatMeMessageContext(ctx context.Context, channel, text string) (*MeMessageResponse, error) {
+	body := url.Values{}
+	body.Add("channel", channel)
+	body.Add("text", text)
+
+	response := &MeMessageResponse{}
+	if err := c.PostContext(ctx, "chat.meMessage", body, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// addJSON adds the JSON encoded value to the values, if ok
+func addJSON(values url.Values, key string, value interface{}, ok bool) {
+	if !ok {
+		return
+	}
+
+	encoded, _ := json.Marshal(value)
+	values.Add(key, string(encoded))
+}
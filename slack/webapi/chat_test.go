@@ -0,0 +1,191 @@
+package webapi
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// formResponder records the posted form and answers with the response
+func formResponder(form *url.Values, response interface{}) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		if err := req.ParseForm(); err != nil {
+			return nil, err
+		}
+		*form = req.Form
+
+		return httpmock.NewJsonResponse(200, response)
+	}
+}
+
+func TestChatUpdate(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var form url.Values
+	httpmock.RegisterResponder("POST", "https://slack.com/api/chat.update", formResponder(&form, &UpdateMessageResponse{
+		APIResponse: APIResponse{OK: true},
+		Channel:     "C1",
+		TimeStamp:   "1.2",
+		Text:        "updated",
+	}))
+
+	message := NewUpdateMessage("C1", "1.2", "updated")
+	message.Blocks = []Block{NewDividerBlock()}
+
+	client := NewClient("123")
+	response, err := client.ChatUpdate(message)
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if response.TimeStamp != "1.2" || response.Text != "updated" {
+		t.Errorf("unexpected response %#v", response)
+	}
+
+	if form.Get("channel") != "C1" || form.Get("ts") != "1.2" || form.Get("blocks") != `[{"type":"divider"}]` {
+		t.Errorf("unexpected request %v", form)
+	}
+}
+
+func TestChatDelete(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var form url.Values
+	httpmock.RegisterResponder("POST", "https://slack.com/api/chat.delete", formResponder(&form, &DeleteMessageResponse{
+		APIResponse: APIResponse{OK: true},
+		Channel:     "C1",
+		TimeStamp:   "1.2",
+	}))
+
+	client := NewClient("123")
+	response, err := client.ChatDelete("C1", "1.2")
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if response.Channel != "C1" || form.Get("ts") != "1.2" {
+		t.Errorf("unexpected response %#v for %v", response, form)
+	}
+}
+
+func TestChatPostEphemeral(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var form url.Values
+	httpmock.RegisterResponder("POST", "https://slack.com/api/chat.postEphemeral", formResponder(&form, &EphemeralMessageResponse{
+		APIResponse:      APIResponse{OK: true},
+		MessageTimeStamp: "1.3",
+	}))
+
+	message := NewEphemeralMessage("C1", "U1", "only for you")
+	message.ThreadTimeStamp = "1.0"
+
+	client := NewClient("123")
+	response, err := client.ChatPostEphemeral(message)
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if response.MessageTimeStamp != "1.3" {
+		t.Errorf("unexpected response %#v", response)
+	}
+
+	if form.Get("user") != "U1" || form.Get("thread_ts") != "1.0" || form.Get("text") != "only for you" {
+		t.Errorf("unexpected request %v", form)
+	}
+}
+
+func TestChatScheduleMessage(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	postAt := time.Unix(1700000000, 0)
+
+	var form url.Values
+	httpmock.RegisterResponder("POST", "https://slack.com/api/chat.scheduleMessage", formResponder(&form, &ScheduledMessageResponse{
+		APIResponse:        APIResponse{OK: true},
+		Channel:            "C1",
+		ScheduledMessageID: "Q1",
+		PostAt:             postAt.Unix(),
+	}))
+
+	var deleted url.Values
+	httpmock.RegisterResponder("POST", "https://slack.com/api/chat.deleteScheduledMessage", formResponder(&deleted, &APIResponse{OK: true}))
+
+	client := NewClient("123")
+	response, err := client.ChatScheduleMessage(NewScheduledMessage("C1", postAt, "later"))
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if response.ScheduledMessageID != "Q1" || form.Get("post_at") != "1700000000" {
+		t.Errorf("unexpected response %#v for %v", response, form)
+	}
+
+	if err := client.ChatDeleteScheduledMessage("C1", response.ScheduledMessageID); err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if deleted.Get("scheduled_message_id") != "Q1" {
+		t.Errorf("unexpected request %v", deleted)
+	}
+}
+
+func TestChatGetPermalink(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "https://slack.com/api/chat.getPermalink", func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("message_ts") != "1.2" {
+			return httpmock.NewStringResponse(200, `{"ok": false, "error": "message_not_found"}`), nil
+		}
+
+		return httpmock.NewJsonResponse(200, &PermalinkResponse{
+			APIResponse: APIResponse{OK: true},
+			Channel:     "C1",
+			Permalink:   "https://zha.slack.com/archives/C1/p12",
+		})
+	})
+
+	client := NewClient("123")
+	permalink, err := client.ChatGetPermalink("C1", "1.2")
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if permalink != "https://zha.slack.com/archives/C1/p12" {
+		t.Errorf("unexpected permalink %q", permalink)
+	}
+
+	if _, err := client.ChatGetPermalink("C1", "9.9"); ErrorCode(err) != "message_not_found" {
+		t.Errorf("expected message_not_found error, got %#v", err)
+	}
+}
+
+func TestChatMeMessage(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var form url.Values
+	httpmock.RegisterResponder("POST", "https://slack.com/api/chat.meMessage", formResponder(&form, &MeMessageResponse{
+		APIResponse: APIResponse{OK: true},
+		Channel:     "C1",
+		TimeStamp:   "1.4",
+	}))
+
+	client := NewClient("123")
+	response, err := client.ChatMeMessage("C1", "is deploying")
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if response.TimeStamp != "1.4" || form.Get("text") != "is deploying" {
+		t.Errorf("unexpected response %#v for %v", response, form)
+	}
+}
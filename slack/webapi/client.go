@@ -1,87 +1,319 @@
 package webapi
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"time"
 )
 
 const (
-	apiEndpoint = "https://slack.com/api/%s"
+	defaultBaseURL   = "https://slack.com/api/"
+	defaultUserAgent = "go-zha"
 )
 
-
 // Client is the client struct
 type Client struct {
-	token string
+	token      string
+	httpClient *http.Client
+	baseURL    string
+	userAgent  string
+	limiter    *rateLimiter
+	noLimit    bool
+	maxRetries int
+	err        error
+
+	statsMu sync.Mutex
+	stats   Stats
 }
 
-// NewClient returns new Client
-func NewClient(token string) *Client {
-	return &Client{token: token}
+// Option configures the Client
+type Option func(*Client) error
+
+// WithHTTPClient sets the HTTP client used for the requests, e.g. to set a timeout
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) error {
+		if httpClient == nil {
+			return errors.New("http client is nil")
+		}
+
+		c.httpClient = httpClient
+		return nil
+	}
 }
 
-// Get creates get request to the slack web api
-// ex. https://api.slack.com/methods/conversations.history
-func (c *Client) Get(method string, queryParams *url.Values, unmarshaledResponse interface{}) error {
-	endpoint := c.endpointGenerator(method, queryParams)
+// WithBaseURL points the client at a different slack API, e.g. a local fake
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) error {
+		parsed, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
 
-	resp, err := http.Get(endpoint.String())
-	if err != nil {
-		switch e := err.(type) {
-		case *url.Error:
-			return e
-		default:
-			return fmt.Errorf("error on HTTP GET request. %#v", e)
+		if parsed.Scheme == "" || parsed.Host == "" {
+			return fmt.Errorf("base url %q is not absolute", baseURL)
+		}
+
+		if !strings.HasSuffix(baseURL, "/") {
+			baseURL += "/"
 		}
+
+		c.baseURL = baseURL
+		return nil
 	}
+}
 
-	defer resp.Body.Close()
+// WithUserAgent sets the User-Agent header of the requests
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) error {
+		c.userAgent = userAgent
+		return nil
+	}
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return NewResponseError("response status error. status: %d", resp)
+// WithoutRateLimit disables spacing the calls by the tiers of the methods.
+// Rate limited calls are still retried after the time slack asks for.
+func WithoutRateLimit() Option {
+	return func(c *Client) error {
+		c.noLimit = true
+		return nil
 	}
+}
 
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return err
+// WithMethodTier sets the rate limit tier of a method. It has no effect
+// together with WithoutRateLimit.
+func WithMethodTier(method string, tier Tier) Option {
+	return func(c *Client) error {
+		if tier.PerMinute <= 0 || tier.Burst <= 0 {
+			return fmt.Errorf("invalid tier %+v of %s", tier, method)
+		}
+
+		c.limiter.tiers[method] = tier
+		return nil
 	}
+}
 
-	if err := json.Unmarshal(body, &unmarshaledResponse); err != nil {
-		return err
+// WithMaxRetries sets how often a call is retried when slack answers with
+// HTTP 429, after waiting for the time given by the Retry-After header
+func WithMaxRetries(retries int) Option {
+	return func(c *Client) error {
+		if retries < 0 {
+			return errors.New("max retries must not be negative")
+		}
+
+		c.maxRetries = retries
+		return nil
 	}
+}
 
-	return nil
+// NewClientE returns new Client, or the error of the first invalid option.
+func NewClientE(token string, opts ...Option) (*Client, error) {
+	c := NewClient(token, opts...)
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return c, nil
+}
+
+// NewClient returns new Client. An invalid option is returned by every call
+// of the client, use NewClientE to get it right away.
+func NewClient(token string, opts ...Option) *Client {
+	c := &Client{
+		token:      token,
+		httpClient: http.DefaultClient,
+		baseURL:    defaultBaseURL,
+		userAgent:  defaultUserAgent,
+		limiter:    newRateLimiter(),
+		maxRetries: defaultMaxRetries,
+	}
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil && c.err == nil {
+			c.err = err
+		}
+	}
+
+	if c.noLimit {
+		c.limiter = nil
+	}
+
+	return c
+}
+
+// Get creates get request to the slack web api
+// ex. https://api.slack.com/methods/conversations.history
+func (c *Client) Get(method string, queryParams *url.Values, unmarshaledResponse interface{}) error {
+	return c.GetContext(context.Background(), method, queryParams, unmarshaledResponse)
+}
+
+// GetContext creates get request to the slack web api, which is cancelled with the context
+func (c *Client) GetContext(ctx context.Context, method string, queryParams *url.Values, unmarshaledResponse interface{}) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	endpoint := c.endpoint(method)
+	channel := ""
+	if queryParams != nil {
+		endpoint.RawQuery = queryParams.Encode()
+		channel = queryParams.Get("channel")
+	}
+
+	return c.call(ctx, method, channel, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	}, unmarshaledResponse)
 }
 
 // RtmStart begins a Real Time Messaging API session and
 // reserves your application a specific URL with which to connect via websocket.
 func (c *Client) RtmStart() (*RtmStart, error) {
+	return c.RtmStartContext(context.Background())
+}
+
+// RtmStartContext is RtmStart with a context
+func (c *Client) RtmStartContext(ctx context.Context) (*RtmStart, error) {
 	rtmStart := &RtmStart{}
-	if err := c.Get("rtm.start", nil, &rtmStart); err != nil {
+	if err := c.GetContext(ctx, "rtm.start", nil, &rtmStart); err != nil {
 		return nil, err
 	}
 
 	return rtmStart, nil
 }
 
+// AuthTest checks the authentication and tells who the token belongs to.
+func (c *Client) AuthTest() (*AuthTest, error) {
+	return c.AuthTestContext(context.Background())
+}
+
+// AuthTestContext is AuthTest with a context
+func (c *Client) AuthTestContext(ctx context.Context) (*AuthTest, error) {
+	authTest := &AuthTest{}
+	if err := c.PostContext(ctx, "auth.test", url.Values{}, &authTest); err != nil {
+		return nil, err
+	}
+
+	return authTest, nil
+}
+
+// AppsConnectionsOpen generates a temporary Socket Mode WebSocket URL.
+// The client has to be created with an app-level token.
+func (c *Client) AppsConnectionsOpen() (*AppsConnectionsOpen, error) {
+	return c.AppsConnectionsOpenContext(context.Background())
+}
+
+// AppsConnectionsOpenContext is AppsConnectionsOpen with a context
+func (c *Client) AppsConnectionsOpenContext(ctx context.Context) (*AppsConnectionsOpen, error) {
+	connection := &AppsConnectionsOpen{}
+	if err := c.PostContext(ctx, "apps.connections.open", url.Values{}, &connection); err != nil {
+		return nil, err
+	}
+
+	return connection, nil
+}
+
 // Post creates post request to the slack api
 func (c *Client) Post(method string, body url.Values, response interface{}) error {
-	endpoint := c.endpointGenerator(method, nil)
+	return c.PostContext(context.Background(), method, body, response)
+}
 
-	resp, err := http.PostForm(endpoint.String(), body)
-	if err != nil {
-		switch e := err.(type) {
-		case *url.Error:
-			return e
-		default:
-			panic(fmt.Sprintf("error on http post request %#v", e))
+// PostContext creates post request to the slack api, which is cancelled with the context
+func (c *Client) PostContext(ctx context.Context, method string, body url.Values, response interface{}) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	endpoint := c.endpoint(method).String()
+	encoded := body.Encode()
+
+	return c.call(ctx, method, body.Get("channel"), func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(encoded))
+		if err != nil {
+			return nil, err
 		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		return req, nil
+	}, response)
+}
+
+// call waits for the rate limit of the method and sends the request, rate
+// limited requests are retried after the time slack asks for.
+func (c *Client) call(ctx context.Context, method, channel string, newRequest func() (*http.Request, error), response interface{}) error {
+	key := method
+	if c.limiter != nil {
+		key = c.limiter.key(method, channel)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := c.wait(ctx, method, key); err != nil {
+			return err
+		}
+
+		req, err := newRequest()
+		if err != nil {
+			return err
+		}
+
+		err = c.do(method, req, response)
+
+		apiErr, ok := err.(*APIError)
+		if !ok || apiErr.Code != ErrRateLimited.Code || attempt >= c.maxRetries {
+			return err
+		}
+
+		if c.limiter != nil {
+			c.limiter.block(key, time.Now().Add(apiErr.RetryAfter))
+			continue
+		}
+
+		c.statsMu.Lock()
+		c.stats.Waited += apiErr.RetryAfter
+		c.statsMu.Unlock()
+
+		timer := time.NewTimer(apiErr.RetryAfter)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// do sends the authorized request and decodes the response. Responses which
+// are not ok are returned as *APIError.
+func (c *Client) do(method string, req *http.Request, response interface{}) error {
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("User-Agent", c.userAgent)
+
+	c.statsMu.Lock()
+	c.stats.Requests++
+	c.statsMu.Unlock()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
 	}
 
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusTooManyRequests {
+		c.statsMu.Lock()
+		c.stats.RateLimited++
+		c.statsMu.Unlock()
+
+		apiErr := NewAPIError(method, APIResponse{Error: ErrRateLimited.Code})
+		apiErr.RetryAfter = retryAfter(resp)
+		return apiErr
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return NewResponseError(fmt.Sprintf("response status error. status %d.", resp.StatusCode), resp)
 	}
@@ -91,35 +323,108 @@ func (c *Client) Post(method string, body url.Values, response interface{}) erro
 		return err
 	}
 
-	if err := json.Unmarshal(readResp, response); err != nil {
+	status := APIResponse{}
+	if err := json.Unmarshal(readResp, &status); err != nil {
 		return err
 	}
 
-	return nil
+	if !status.OK {
+		return NewAPIError(method, status)
+	}
+
+	return json.Unmarshal(readResp, response)
 }
 
 // PostMessage creates post request to the chat.postMessage slack method
-func (c *Client) PostMessage(message *PostMessage) (*APIResponse, error) {
-	response := &APIResponse{}
-	err := c.Post("chat.postMessage", message.ToURLValues(), &response)
+func (c *Client) PostMessage(message *PostMessage) (*PostMessageResponse, error) {
+	return c.PostMessageContext(context.Background(), message)
+}
+
+// PostMessageContext is PostMessage with a context
+func (c *Client) PostMessageContext(ctx context.Context, message *PostMessage) (*PostMessageResponse, error) {
+	if err := ValidateBlocks(message.Blocks, MaxMessageBlocks); err != nil {
+		return nil, err
+	}
+
+	response := &PostMessageResponse{}
+	err := c.PostContext(ctx, "chat.postMessage", message.ToURLValues(), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// ViewsOpen opens a modal view for the user who triggered the interaction
+func (c *Client) ViewsOpen(triggerID string, view *View) (*ViewResponse, error) {
+	return c.ViewsOpenContext(context.Background(), triggerID, view)
+}
+
+// ViewsOpenContext is ViewsOpen with a context
+func (c *Client) ViewsOpenContext(ctx context.Context, triggerID string, view *View) (*ViewResponse, error) {
+	if err := view.Validate(); err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(view)
 	if err != nil {
 		return nil, err
 	}
 
+	body := url.Values{}
+	body.Add("trigger_id", triggerID)
+	body.Add("view", string(encoded))
+
+	response := &ViewResponse{}
+	if err := c.PostContext(ctx, "views.open", body, &response); err != nil {
+		return nil, err
+	}
+
 	return response, nil
 }
 
-func (c *Client) endpointGenerator(method string, params *url.Values) *url.URL {
-	if params == nil {
-		params = &url.Values{}
+// PostResponse posts a message to the response_url of a slash command or
+// an interaction
+func (c *Client) PostResponse(responseURL string, message *ResponseMessage) error {
+	return c.PostResponseContext(context.Background(), responseURL, message)
+}
+
+// PostResponseContext is PostResponse with a context
+func (c *Client) PostResponseContext(ctx context.Context, responseURL string, message *ResponseMessage) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
 	}
-	params.Add("token", c.token)
 
-	url, err := url.Parse(fmt.Sprintf(apiEndpoint, method))
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return NewResponseError(fmt.Sprintf("response status error. status %d.", resp.StatusCode), resp)
+	}
+
+	return nil
+}
+
+func (c *Client) endpoint(method string) *url.URL {
+	endpoint, err := url.Parse(c.baseURL + method)
 	if err != nil {
 		panic(err.Error())
 	}
-	url.RawQuery = params.Encode()
 
-	return url
+	return endpoint
 }
@@ -3,8 +3,14 @@
 package webapi
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/jarcoal/httpmock"
 )
@@ -183,8 +189,11 @@ func TestPostMessage(t *testing.T) {
 	httpmock.Activate()
 	defer httpmock.DeactivateAndReset()
 
-	response := &APIResponse{OK: true}
-	responder, _ := httpmock.NewJsonResponder(200, response)
+	responder, _ := httpmock.NewJsonResponder(200, &PostMessageResponse{
+		APIResponse: APIResponse{OK: true},
+		Channel:     "channel",
+		TimeStamp:   "1355517523.000005",
+	})
 	httpmock.RegisterResponder(
 		"POST",
 		"https://slack.com/api/chat.postMessage",
@@ -203,4 +212,224 @@ func TestPostMessage(t *testing.T) {
 		t.Errorf("OK status is wrong %#v", response)
 	}
 
+	if response.TimeStamp != "1355517523.000005" {
+		t.Errorf("message timestamp is wrong %#v", response)
+	}
+}
+
+func TestAuthTest(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	responder, _ := httpmock.NewJsonResponder(200, &AuthTest{
+		APIResponse: APIResponse{OK: true},
+		UserID:      "U123",
+		BotID:       "B123",
+	})
+	httpmock.RegisterResponder("POST", "https://slack.com/api/auth.test", responder)
+
+	client := NewClient("123")
+	authTest, err := client.AuthTest()
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if authTest.UserID != "U123" || authTest.BotID != "B123" {
+		t.Errorf("unexpected auth.test response %#v", authTest)
+	}
+}
+
+func TestAppsConnectionsOpen(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	testURL := "wss://localhost/link"
+	responder, _ := httpmock.NewJsonResponder(200, &AppsConnectionsOpen{
+		APIResponse: APIResponse{OK: true},
+		URL:         testURL,
+	})
+	httpmock.RegisterResponder("POST", "https://slack.com/api/apps.connections.open", responder)
+
+	client := NewClient("xapp-123")
+	connection, err := client.AppsConnectionsOpen()
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if connection.URL != testURL {
+		t.Errorf("URL is not returned properly %#v", connection)
+	}
+}
+
+func TestPostResponse(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	responseURL := "https://hooks.slack.com/commands/T1/1/abc"
+	var received ResponseMessage
+	httpmock.RegisterResponder("POST", responseURL, func(req *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(req.Body).Decode(&received); err != nil {
+			return nil, err
+		}
+
+		return httpmock.NewStringResponse(200, "ok"), nil
+	})
+
+	client := NewClient("123")
+	if err := client.PostResponse(responseURL, NewResponseMessage("done", true)); err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if received.ResponseType != "in_channel" || received.Text != "done" {
+		t.Errorf("unexpected response message %#v", received)
+	}
+}
+
+func TestViewsOpen(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var sent View
+	httpmock.RegisterResponder("POST", "https://slack.com/api/views.open", func(req *http.Request) (*http.Response, error) {
+		if err := req.ParseForm(); err != nil {
+			return nil, err
+		}
+
+		if req.PostForm.Get("trigger_id") != "123.456" {
+			return httpmock.NewStringResponse(400, "trigger_id is missing"), nil
+		}
+
+		if err := json.Unmarshal([]byte(req.PostForm.Get("view")), &sent); err != nil {
+			return nil, err
+		}
+
+		return httpmock.NewJsonResponse(200, &ViewResponse{
+			APIResponse: APIResponse{OK: true},
+			View:        &View{ID: "V1", Type: "modal", CallbackID: sent.CallbackID},
+		})
+	})
+
+	client := NewClient("123")
+	response, err := client.ViewsOpen("123.456", NewModal("deploy", "Deploy"))
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if sent.Type != "modal" || sent.Title.Text != "Deploy" {
+		t.Errorf("unexpected view sent %#v", sent)
+	}
+
+	if response.View.ID != "V1" || response.View.CallbackID != "deploy" {
+		t.Errorf("unexpected view returned %#v", response.View)
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/auth.test" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer xoxb-123" || r.Header.Get("User-Agent") != "deploy-bot" {
+			json.NewEncoder(w).Encode(&APIResponse{OK: false})
+			return
+		}
+
+		json.NewEncoder(w).Encode(&AuthTest{APIResponse: APIResponse{OK: true}, UserID: "U123"})
+	}))
+	defer server.Close()
+
+	client := NewClient("xoxb-123",
+		WithBaseURL(server.URL+"/api"),
+		WithUserAgent("deploy-bot"),
+		WithHTTPClient(&http.Client{Timeout: time.Second}),
+	)
+
+	auth, err := client.AuthTest()
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if auth.UserID != "U123" {
+		t.Errorf("unexpected user %#v", auth)
+	}
+}
+
+func TestClientContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient("xoxb-123", WithBaseURL(server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.AuthTestContext(ctx); err == nil {
+		t.Error("expected cancelled request to fail")
+	}
+}
+
+func TestClientInvalidOption(t *testing.T) {
+	client := NewClient("xoxb-123", WithBaseURL("slack"))
+
+	if err := client.Post("auth.test", url.Values{}, &APIResponse{}); err == nil {
+		t.Error("expected error of invalid base url")
+	}
+
+	if _, err := NewClientE("xoxb-123", WithBaseURL("slack")); err == nil {
+		t.Error("expected NewClientE to return the error of the invalid base url")
+	}
+
+	if _, err := NewClientE("xoxb-123", WithBaseURL("https://slack.test/api")); err != nil {
+		t.Errorf("unexpected error %#v", err)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "https://slack.com/api/chat.postMessage",
+		httpmock.NewStringResponder(200, `{
+			"ok": false,
+			"error": "invalid_blocks",
+			"warning": "missing_charset",
+			"response_metadata": {"messages": ["[ERROR] must provide a string [json-pointer:/blocks/0/text]"]}
+		}`),
+	)
+	httpmock.RegisterResponder("POST", "https://slack.com/api/auth.test",
+		httpmock.NewStringResponder(200, `{"ok": false, "error": "invalid_auth"}`),
+	)
+	httpmock.RegisterResponder("GET", "https://slack.com/api/rtm.start",
+		httpmock.NewStringResponder(429, `{"ok": false, "error": "ratelimited"}`),
+	)
+
+	client := NewClient("123")
+
+	_, err := client.PostMessage(NewPostMessage("channel", "some message"))
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("expected api error, got %#v", err)
+	}
+
+	if apiErr.Method != "chat.postMessage" || apiErr.Code != "invalid_blocks" || apiErr.Warning != "missing_charset" {
+		t.Errorf("unexpected api error %#v", apiErr)
+	}
+	if len(apiErr.Messages) != 1 || ErrorCode(err) != "invalid_blocks" {
+		t.Errorf("unexpected api error messages %#v", apiErr)
+	}
+
+	if _, err := client.AuthTest(); !errors.Is(err, ErrInvalidAuth) || errors.Is(err, ErrNotInChannel) {
+		t.Errorf("expected invalid_auth error, got %#v", err)
+	}
+
+	client = NewClient("123", WithMaxRetries(0))
+	if _, err := client.RtmStart(); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected ratelimited error, got %#v", err)
+	}
 }
@@ -0,0 +1,198 @@
+package webapi
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ConversationsListParams filters the conversations of conversations.list
+type ConversationsListParams struct {
+	// Types is a list of public_channel, private_channel, mpim and im.
+	Types           []string
+	ExcludeArchived bool
+	TeamID          string
+	// Limit is the page size.
+	Limit int
+}
+
+func (p ConversationsListParams) values() url.Values {
+	values := url.Values{}
+	values.Add("limit", pageSize(p.Limit))
+	if len(p.Types) > 0 {
+		values.Add("types", strings.Join(p.Types, ","))
+	}
+	if p.ExcludeArchived {
+		values.Add("exclude_archived", "true")
+	}
+	if p.TeamID != "" {
+		values.Add("team_id", p.TeamID)
+	}
+
+	return values
+}
+
+// ConversationsListResponse is a page of conversations.list
+type ConversationsListResponse struct {
+	APIResponse
+	Channels []Channel `json:"channels"`
+}
+
+// ConversationsList returns every conversation
+func (c *Client) ConversationsList(params ConversationsListParams) ([]Channel, error) {
+	return c.ConversationsListContext(context.Background(), params)
+}
+
+// ConversationsListContext is ConversationsList with a context
+func (c *Client) ConversationsListContext(ctx context.Context, params ConversationsListParams) ([]Channel, error) {
+	var channels []Channel
+	err := c.ConversationsListPages(ctx, params, func(page []Channel) error {
+		channels = append(channels, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return channels, nil
+}
+
+// ConversationsListPages passes the conversations page by page to handle
+func (c *Client) ConversationsListPages(ctx context.Context, params ConversationsListParams, handle func([]Channel) error) error {
+	return c.Paginate(ctx, "conversations.list", params.values(), func() Page {
+		return &ConversationsListResponse{}
+	}, func(page Page) error {
+		return handle(page.(*ConversationsListResponse).Channels)
+	})
+}
+
+// ConversationsMembersResponse is a page of conversations.members
+type ConversationsMembersResponse struct {
+	APIResponse
+	Members []string `json:"members"`
+}
+
+// ConversationsMembers returns the IDs of every member of the channel
+func (c *Client) ConversationsMembers(channel string) ([]string, error) {
+	return c.ConversationsMembersContext(context.Background(), channel)
+}
+
+// ConversationsMembersContext is ConversationsMembers with a context
+func (c *Client) ConversationsMembersContext(ctx context.Context, channel string) ([]string, error) {
+	var members []string
+	err := c.ConversationsMembersPages(ctx, channel, 0, func(page []string) error {
+		members = append(members, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// ConversationsMembersPages passes the member IDs of the channel page by page to handle
+func (c *Client) ConversationsMembersPages(ctx context.Context, channel string, limit int, handle func([]string) error) error {
+	values := url.Values{}
+	values.Add("channel", channel)
+	values.Add("limit", pageSize(limit))
+
+	return c.Paginate(ctx, "conversations.members", values, func() Page {
+		return &ConversationsMembersResponse{}
+	}, func(page Page) error {
+		return handle(page.(*ConversationsMembersResponse).Members)
+	})
+}
+
+// HistoryParams selects the messages of conversations.history and
+// conversations.replies
+type HistoryParams struct {
+	Channel string
+	// TimeStamp is the thread of conversations.replies.
+	TimeStamp string
+	Oldest    string
+	Latest    string
+	Inclusive bool
+	// Limit is the page size.
+	Limit int
+}
+
+func (p HistoryParams) values() url.Values {
+	values := url.Values{}
+	values.Add("channel", p.Channel)
+	values.Add("limit", pageSize(p.Limit))
+	if p.TimeStamp != "" {
+		values.Add("ts", p.TimeStamp)
+	}
+	if p.Oldest != "" {
+		values.Add("oldest", p.Oldest)
+	}
+	if p.Latest != "" {
+		values.Add("latest", p.Latest)
+	}
+	if p.Inclusive {
+		values.Add("inclusive", strconv.FormatBool(p.Inclusive))
+	}
+
+	return values
+}
+
+// ConversationsHistoryResponse is a page of conversations.history or
+// conversations.replies
+type ConversationsHistoryResponse struct {
+	APIResponse
+	Messages []Message `json:"messages"`
+	HasMore  bool      `json:"has_more"`
+}
+
+// ConversationsHistory returns the messages of the channel, newest first
+func (c *Client) ConversationsHistory(params HistoryParams) ([]Message, error) {
+	return c.ConversationsHistoryContext(context.Background(), params)
+}
+
+// ConversationsHistoryContext is ConversationsHistory with a context
+func (c *Client) ConversationsHistoryContext(ctx context.Context, params HistoryParams) ([]Message, error) {
+	return c.collectMessages(ctx, "conversations.history", params)
+}
+
+// ConversationsHistoryPages passes the messages of the channel page by page to handle
+func (c *Client) ConversationsHistoryPages(ctx context.Context, params HistoryParams, handle func([]Message) error) error {
+	return c.messagePages(ctx, "conversations.history", params, handle)
+}
+
+// ConversationsReplies returns the messages of the thread, starting with its parent
+func (c *Client) ConversationsReplies(params HistoryParams) ([]Message, error) {
+	return c.ConversationsRepliesContext(context.Background(), params)
+}
+
+// ConversationsRepliesContext is ConversationsReplies with a context
+func (c *Client) ConversationsRepliesContext(ctx context.Context, params HistoryParams) ([]Message, error) {
+	return c.collectMessages(ctx, "conversations.replies", params)
+}
+
+// ConversationsRepliesPages passes the messages of the thread page by page to handle
+func (c *Client) ConversationsRepliesPages(ctx context.Context, params HistoryParams, handle func([]Message) error) error {
+	return c.messagePages(ctx, "conversations.replies", params, handle)
+}
+
+func (c *Client) collectMessages(ctx context.Context, method string, params HistoryParams) ([]Message, error) {
+	var messages []Message
+	err := c.messagePages(ctx, method, params, func(page []Message) error {
+		messages = append(messages, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (c *Client) messagePages(ctx context.Context, method string, params HistoryParams, handle func([]Message) error) error {
+	return c.Paginate(ctx, method, params.values(), func() Page {
+		return &ConversationsHistoryResponse{}
+	}, func(page Page) error {
+		return handle(page.(*ConversationsHistoryResponse).Messages)
+	})
+}
@@ -1,6 +1,11 @@
 package webapi
 
-import "net/http"
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
 
 // ResponseError type
 type ResponseError struct {
@@ -16,3 +21,68 @@ func NewResponseError(err string, resp *http.Response) *ResponseError {
 func (r *ResponseError) Error() string {
 	return r.Err
 }
+
+// APIError is returned when slack answers a call with "ok": false
+// ex. https://api.slack.com/web#evaluating_responses
+type APIError struct {
+	Method   string
+	Code     string
+	Warning  string
+	Messages []string
+	// RetryAfter is how long slack asks to wait before the next call of a
+	// rate limited method.
+	RetryAfter time.Duration
+}
+
+// NewAPIError creates new APIError from the response of the method
+func NewAPIError(method string, response APIResponse) *APIError {
+	err := &APIError{Method: method, Code: response.Error, Warning: response.Warning}
+	if response.ResponseMetadata != nil {
+		err.Messages = response.ResponseMetadata.Messages
+	}
+
+	return err
+}
+
+func (e *APIError) Error() string {
+	msg := e.Code
+	if e.Method != "" {
+		msg = e.Method + " failed: " + e.Code
+	}
+
+	if len(e.Messages) > 0 {
+		msg += " (" + strings.Join(e.Messages, ", ") + ")"
+	}
+
+	return msg
+}
+
+// Is reports whether the target is an APIError with the same code, so the
+// sentinel errors can be matched with errors.Is.
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	return ok && t.Code == e.Code
+}
+
+// Common slack error codes, to be matched with errors.Is
+var (
+	ErrNotInChannel    = &APIError{Code: "not_in_channel"}
+	ErrChannelNotFound = &APIError{Code: "channel_not_found"}
+	ErrInvalidAuth     = &APIError{Code: "invalid_auth"}
+	ErrNotAuthed       = &APIError{Code: "not_authed"}
+	ErrAccountInactive = &APIError{Code: "account_inactive"}
+	ErrMissingScope    = &APIError{Code: "missing_scope"}
+	ErrRateLimited     = &APIError{Code: "ratelimited"}
+	ErrAlreadyReacted  = &APIError{Code: "already_reacted"}
+	ErrNoReaction      = &APIError{Code: "no_reaction"}
+)
+
+// ErrorCode returns the slack error code of the error, if it is an APIError
+func ErrorCode(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+
+	return ""
+}
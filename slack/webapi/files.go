@@ -0,0 +1,327 @@
+package webapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// File is a file shared in slack
+type File struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Title      string `json:"title"`
+	Mimetype   string `json:"mimetype"`
+	Filetype   string `json:"filetype"`
+	Size       int64  `json:"size"`
+	URLPrivate string `json:"url_private"`
+	Permalink  string `json:"permalink"`
+}
+
+// FileUpload is a file or a snippet to upload
+type FileUpload struct {
+	Filename string
+	// Reader is the content of the file, it is streamed to slack.
+	Reader io.Reader
+	// Length is the size of the content, it is required by the external
+	// upload flow. If it is not set the content is read into memory first.
+	Length int64
+	// Content is the text of a snippet, it is used if Reader is nil.
+	Content string
+	// Filetype or snippet type, e.g. text, csv or go.
+	Filetype        string
+	Title           string
+	InitialComment  string
+	Channels        []string
+	ThreadTimeStamp string
+}
+
+func (upload *FileUpload) fields() url.Values {
+	values := url.Values{}
+	values.Add("filename", upload.Filename)
+	if upload.Filetype != "" {
+		values.Add("filetype", upload.Filetype)
+	}
+	if upload.Title != "" {
+		values.Add("title", upload.Title)
+	}
+	if upload.InitialComment != "" {
+		values.Add("initial_comment", upload.InitialComment)
+	}
+	if len(upload.Channels) > 0 {
+		values.Add("channels", strings.Join(upload.Channels, ","))
+	}
+	if upload.ThreadTimeStamp != "" {
+		values.Add("thread_ts", upload.ThreadTimeStamp)
+	}
+
+	return values
+}
+
+// reader returns the content of the upload
+func (upload *FileUpload) reader() io.Reader {
+	if upload.Reader != nil {
+		return upload.Reader
+	}
+
+	return strings.NewReader(upload.Content)
+}
+
+// FileResponse is returned by files.upload
+type FileResponse struct {
+	APIResponse
+	File File `json:"file"`
+}
+
+// FilesUpload uploads the file with a multipart request to files.upload.
+// The content is streamed, so the upload is not retried when rate limited.
+func (c *Client) FilesUpload(upload *FileUpload) (*File, error) {
+	return c.FilesUploadContext(context.Background(), upload)
+}
+
+// FilesUploadContext is FilesUpload with a context
+func (c *Client) FilesUploadContext(ctx context.Context, upload *FileUpload) (*File, error) {
+	fields := upload.fields()
+	if upload.Reader == nil {
+		fields.Add("content", upload.Content)
+	}
+
+	response := &FileResponse{}
+	if err := c.PostMultipartContext(ctx, "files.upload", fields, upload.Filename, upload.Reader, response); err != nil {
+		return nil, err
+	}
+
+	return &response.File, nil
+}
+
+// PostMultipartContext posts the fields and the streamed file as multipart
+// form to the slack api. The request is sent once, it is not retried when
+// rate limited.
+func (c *Client) PostMultipartContext(ctx context.Context, method string, fields url.Values, filename string, file io.Reader, response interface{}) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	if err := c.wait(ctx, method, method); err != nil {
+		return err
+	}
+
+	body, contentType := multipartBody(fields, filename, file)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(method).String(), body)
+	if err != nil {
+		body.Close()
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	return c.do(method, req, response)
+}
+
+// multipartBody streams the multipart form through a pipe
+func multipartBody(fields url.Values, filename string, file io.Reader) (io.ReadCloser, string) {
+	reader, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+
+	go func() {
+		err := writeMultipart(form, fields, filename, file)
+		if err == nil {
+			err = form.Close()
+		}
+
+		writer.CloseWithError(err)
+	}()
+
+	return reader, form.FormDataContentType()
+}
+
+func writeMultipart(form *multipart.Writer, fields url.Values, filename string, file io.Reader) error {
+	for key, values := range fields {
+		for _, value := range values {
+			if err := form.WriteField(key, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	if file == nil {
+		return nil
+	}
+
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(part, file)
+	return err
+}
+
+// UploadURLExternalResponse is returned by files.getUploadURLExternal
+type UploadURLExternalResponse struct {
+	APIResponse
+	UploadURL string `json:"upload_url"`
+	FileID    string `json:"file_id"`
+}
+
+// FilesGetUploadURLExternal returns the URL the content of a file is uploaded to
+func (c *Client) FilesGetUploadURLExternal(filename string, length int64, snippetType string) (*UploadURLExternalResponse, error) {
+	return c.FilesGetUploadURLExternalContext(context.Background(), filename, length, snippetType)
+}
+
+// FilesGetUploadURLExternalContext is FilesGetUploadURLExternal with a context
+func (c *Client) FilesGetUploadURLExternalContext(ctx context.Context, filename string, length int64, snippetType string) (*UploadURLExternalResponse, error) {
+	body := url.Values{}
+	body.Add("filename", filename)
+	body.Add("length", strconv.FormatInt(length, 10))
+	if snippetType != "" {
+		body.Add("snippet_type", snippetType)
+	}
+
+	response := &UploadURLExternalResponse{}
+	if err := c.PostContext(ctx, "files.getUploadURLExternal", body, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// UploadExternalContext streams the content of a file to the upload URL
+// returned by files.getUploadURLExternal
+func (c *Client) UploadExternalContext(ctx context.Context, uploadURL string, content io.Reader, length int64) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, content)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = length
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return NewResponseError(fmt.Sprintf("response status error. status %d.", resp.StatusCode), resp)
+	}
+
+	return nil
+}
+
+// CompleteUploadParams shares the uploaded files
+type CompleteUploadParams struct {
+	Files     []CompleteUploadFile
+	ChannelID string
+	// Channels shares the files to several channels, instead of ChannelID.
+	Channels        []string
+	InitialComment  string
+	ThreadTimeStamp string
+}
+
+// CompleteUploadFile is an uploaded file of files.completeUploadExternal
+type CompleteUploadFile struct {
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"`
+}
+
+// CompleteUploadResponse is returned by files.completeUploadExternal
+type CompleteUploadResponse struct {
+	APIResponse
+	Files []File `json:"files"`
+}
+
+// FilesCompleteUploadExternal finishes the upload of the files and shares
+// them to the channel
+func (c *Client) FilesCompleteUploadExternal(params CompleteUploadParams) ([]File, error) {
+	return c.FilesCompleteUploadExternalContext(context.Background(), params)
+}
+
+// FilesCompleteUploadExternalContext is FilesCompleteUploadExternal with a context
+func (c *Client) FilesCompleteUploadExternalContext(ctx context.Context, params CompleteUploadParams) ([]File, error) {
+	files, err := json.Marshal(params.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	body := url.Values{}
+	body.Add("files", string(files))
+	if params.ChannelID != "" {
+		body.Add("channel_id", params.ChannelID)
+	}
+	if len(params.Channels) > 0 {
+		body.Add("channels", strings.Join(params.Channels, ","))
+	}
+	if params.InitialComment != "" {
+		body.Add("initial_comment", params.InitialComment)
+	}
+	if params.ThreadTimeStamp != "" {
+		body.Add("thread_ts", params.ThreadTimeStamp)
+	}
+
+	response := &CompleteUploadResponse{}
+	if err := c.PostContext(ctx, "files.completeUploadExternal", body, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Files, nil
+}
+
+// UploadFile uploads the file with the external upload flow and shares it
+// to its channels
+// ex. https://api.slack.com/messaging/files#uploading_files
+func (c *Client) UploadFile(upload *FileUpload) (*File, error) {
+	return c.UploadFileContext(context.Background(), upload)
+}
+
+// UploadFileContext is UploadFile with a context
+func (c *Client) UploadFileContext(ctx context.Context, upload *FileUpload) (*File, error) {
+	content, length := upload.reader(), upload.Length
+	if length <= 0 {
+		buffered, err := ioutil.ReadAll(content)
+		if err != nil {
+			return nil, err
+		}
+
+		content, length = bytes.NewReader(buffered), int64(len(buffered))
+	}
+
+	target, err := c.FilesGetUploadURLExternalContext(ctx, upload.Filename, length, upload.Filetype)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.UploadExternalContext(ctx, target.UploadURL, content, length); err != nil {
+		return nil, err
+	}
+
+	params := CompleteUploadParams{
+		Files:           []CompleteUploadFile{{ID: target.FileID, Title: upload.Title}},
+		Channels:        upload.Channels,
+		InitialComment:  upload.InitialComment,
+		ThreadTimeStamp: upload.ThreadTimeStamp,
+	}
+
+	files, err := c.FilesCompleteUploadExternalContext(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return &File{ID: target.FileID}, nil
+	}
+
+	return &files[0], nil
+}
@@ -0,0 +1,153 @@
+package webapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFilesUpload(t *testing.T) {
+	var fields map[string]string
+	var content string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/files.upload" || !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			http.NotFound(w, r)
+			return
+		}
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		read, _ := ioutil.ReadAll(file)
+		content = string(read)
+		fields = map[string]string{
+			"filename": header.Filename,
+			"channels": r.FormValue("channels"),
+			"comment":  r.FormValue("initial_comment"),
+		}
+
+		json.NewEncoder(w).Encode(&FileResponse{
+			APIResponse: APIResponse{OK: true},
+			File:        File{ID: "F1", Name: header.Filename, Size: int64(len(read))},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient("123", WithBaseURL(server.URL+"/api"))
+	file, err := client.FilesUpload(&FileUpload{
+		Filename:       "report.csv",
+		Reader:         strings.NewReader("a,b\n1,2\n"),
+		Channels:       []string{"C1", "C2"},
+		InitialComment: "the report",
+	})
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if file.ID != "F1" || file.Size != 8 {
+		t.Errorf("unexpected file %#v", file)
+	}
+
+	if content != "a,b\n1,2\n" || fields["filename"] != "report.csv" || fields["channels"] != "C1,C2" || fields["comment"] != "the report" {
+		t.Errorf("unexpected upload %q %v", content, fields)
+	}
+}
+
+func TestFilesUploadError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&APIResponse{Error: "invalid_channel"})
+	}))
+	defer server.Close()
+
+	client := NewClient("123", WithBaseURL(server.URL+"/api"))
+	_, err := client.FilesUpload(&FileUpload{Filename: "notes.txt", Content: "notes"})
+	if ErrorCode(err) != "invalid_channel" {
+		t.Errorf("unexpected error %#v", err)
+	}
+}
+
+func TestUploadFile(t *testing.T) {
+	var calls []string
+	var uploaded, length, shared string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path)
+
+		switch r.URL.Path {
+		case "/api/files.getUploadURLExternal":
+			length = r.FormValue("length")
+			json.NewEncoder(w).Encode(&UploadURLExternalResponse{
+				APIResponse: APIResponse{OK: true},
+				UploadURL:   server.URL + "/upload/F1",
+				FileID:      "F1",
+			})
+		case "/upload/F1":
+			read, _ := ioutil.ReadAll(r.Body)
+			uploaded = string(read)
+			w.Write([]byte("OK - 5"))
+		case "/api/files.completeUploadExternal":
+			shared = r.FormValue("files") + " " + r.FormValue("channels") + " " + r.FormValue("thread_ts")
+			json.NewEncoder(w).Encode(&CompleteUploadResponse{
+				APIResponse: APIResponse{OK: true},
+				Files:       []File{{ID: "F1", Title: "Notes"}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("123", WithBaseURL(server.URL+"/api"))
+	file, err := client.UploadFile(&FileUpload{
+		Filename:        "notes.txt",
+		Content:         "notes",
+		Title:           "Notes",
+		Channels:        []string{"C1", "C2"},
+		ThreadTimeStamp: "1.2",
+	})
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if file.ID != "F1" || file.Title != "Notes" {
+		t.Errorf("unexpected file %#v", file)
+	}
+
+	if len(calls) != 3 {
+		t.Fatalf("unexpected calls %v", calls)
+	}
+
+	if uploaded != "notes" || length != "5" || shared != `[{"id":"F1","title":"Notes"}] C1,C2 1.2` {
+		t.Errorf("unexpected upload %q %q %q", uploaded, length, shared)
+	}
+}
+
+func TestUploadFileFailedUpload(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/files.getUploadURLExternal" {
+			json.NewEncoder(w).Encode(&UploadURLExternalResponse{
+				APIResponse: APIResponse{OK: true},
+				UploadURL:   server.URL + "/upload/F1",
+				FileID:      "F1",
+			})
+			return
+		}
+
+		http.Error(w, "upload failed", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient("123", WithBaseURL(server.URL+"/api"))
+	_, err := client.UploadFile(&FileUpload{Filename: "notes.txt", Reader: strings.NewReader("notes"), Length: 5})
+	if _, ok := err.(*ResponseError); !ok {
+		t.Errorf("unexpected error %#v", err)
+	}
+}
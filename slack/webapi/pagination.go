@@ -0,0 +1,62 @@
+package webapi
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+// defaultPageSize is the number of items requested per page, slack
+// recommends no more than 200
+const defaultPageSize = 200
+
+// ErrStopPagination can be returned by a page handler to stop paginating
+// without an error
+var ErrStopPagination = errors.New("stop pagination")
+
+// Page is a page of a paginated response
+type Page interface {
+	NextCursor() string
+}
+
+// Paginate calls the list method page by page, following the next_cursor of
+// the responses. Every page is decoded into the value returned by newPage and
+// passed to handle.
+// ex. https://api.slack.com/docs/pagination
+func (c *Client) Paginate(ctx context.Context, method string, params url.Values, newPage func() Page, handle func(Page) error) error {
+	query := url.Values{}
+	for key, values := range params {
+		query[key] = values
+	}
+
+	for {
+		page := newPage()
+		if err := c.GetContext(ctx, method, &query, page); err != nil {
+			return err
+		}
+
+		if err := handle(page); err != nil {
+			if err == ErrStopPagination {
+				return nil
+			}
+
+			return err
+		}
+
+		cursor := page.NextCursor()
+		if cursor == "" {
+			return nil
+		}
+
+		query.Set("cursor", cursor)
+	}
+}
+
+func pageSize(limit int) string {
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+
+	return strconv.Itoa(limit)
+}
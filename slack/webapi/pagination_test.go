@@ -0,0 +1,143 @@
+package webapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// pagedResponder answers with the pages in order, following the cursor
+func pagedResponder(t *testing.T, pages ...string) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		cursor := req.URL.Query().Get("cursor")
+		index := 0
+		if cursor != "" {
+			index = int(cursor[len(cursor)-1] - '0')
+		}
+
+		if index >= len(pages) {
+			t.Errorf("unexpected cursor %q", cursor)
+			return httpmock.NewStringResponse(400, "invalid cursor"), nil
+		}
+
+		return httpmock.NewStringResponse(200, pages[index]), nil
+	}
+}
+
+func TestConversationsList(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var types, limit string
+	responder := pagedResponder(t,
+		`{"ok": true, "channels": [{"id": "C1"}, {"id": "C2"}], "response_metadata": {"next_cursor": "page1"}}`,
+		`{"ok": true, "channels": [{"id": "C3"}], "response_metadata": {"next_cursor": ""}}`,
+	)
+	httpmock.RegisterResponder("GET", "https://slack.com/api/conversations.list", func(req *http.Request) (*http.Response, error) {
+		types = req.URL.Query().Get("types")
+		limit = req.URL.Query().Get("limit")
+		return responder(req)
+	})
+
+	client := NewClient("123")
+	channels, err := client.ConversationsList(ConversationsListParams{Types: []string{"public_channel", "private_channel"}})
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if len(channels) != 3 || channels[0].ID != "C1" || channels[2].ID != "C3" {
+		t.Errorf("unexpected channels %#v", channels)
+	}
+
+	if types != "public_channel,private_channel" || limit != "200" {
+		t.Errorf("unexpected parameters types=%q limit=%q", types, limit)
+	}
+}
+
+func TestConversationsMembersStop(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "https://slack.com/api/conversations.members", pagedResponder(t,
+		`{"ok": true, "members": ["U1", "U2"], "response_metadata": {"next_cursor": "page1"}}`,
+		`{"ok": true, "members": ["U3"]}`,
+	))
+
+	client := NewClient("123")
+
+	pages := 0
+	err := client.ConversationsMembersPages(context.Background(), "C1", 2, func(members []string) error {
+		pages++
+		return ErrStopPagination
+	})
+	if err != nil || pages != 1 {
+		t.Errorf("expected to stop after the first page, got %d pages and %#v", pages, err)
+	}
+
+	members, err := client.ConversationsMembers("C1")
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if len(members) != 3 {
+		t.Errorf("unexpected members %v", members)
+	}
+}
+
+func TestConversationsRepliesError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "https://slack.com/api/conversations.replies", pagedResponder(t,
+		`{"ok": true, "messages": [{"user": "U1", "text": "parent", "ts": "1.0", "thread_ts": "1.0", "reply_count": 1}], "response_metadata": {"next_cursor": "page1"}}`,
+		`{"ok": false, "error": "thread_not_found"}`,
+	))
+
+	client := NewClient("123")
+	_, err := client.ConversationsReplies(HistoryParams{Channel: "C1", TimeStamp: "1.0"})
+	if ErrorCode(err) != "thread_not_found" {
+		t.Errorf("expected error of the second page, got %#v", err)
+	}
+}
+
+func TestConversationsHistory(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "https://slack.com/api/conversations.history", pagedResponder(t,
+		`{"ok": true, "messages": [{"user": "U1", "text": "second", "ts": "2.0"}], "has_more": true, "response_metadata": {"next_cursor": "page1"}}`,
+		`{"ok": true, "messages": [{"user": "U1", "text": "first", "ts": "1.0"}], "has_more": false}`,
+	))
+
+	client := NewClient("123")
+	messages, err := client.ConversationsHistory(HistoryParams{Channel: "C1"})
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if len(messages) != 2 || messages[0].TimeStamp != "2.0" || messages[1].Text != "first" {
+		t.Errorf("unexpected messages %#v", messages)
+	}
+}
+
+func TestUsersList(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "https://slack.com/api/users.list", pagedResponder(t,
+		`{"ok": true, "members": [{"id": "U1", "name": "kochev"}], "response_metadata": {"next_cursor": "page1"}}`,
+		`{"ok": true, "members": [{"id": "U2", "name": "zha", "is_bot": true}]}`,
+	))
+
+	client := NewClient("123")
+	users, err := client.UsersList()
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if len(users) != 2 || users[1].Name != "zha" || !users[1].IsBot {
+		t.Errorf("unexpected users %#v", users)
+	}
+}
@@ -0,0 +1,221 @@
+package webapi
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Tier is the rate limit of slack web API methods
+// ex. https://api.slack.com/docs/rate-limits
+type Tier struct {
+	PerMinute int
+	// Burst is how many calls may be made at once before they are spaced.
+	Burst int
+	// PerChannel limits the calls per channel instead of per method.
+	PerChannel bool
+}
+
+// Rate limit tiers of slack
+var (
+	Tier1 = Tier{PerMinute: 1, Burst: 2}
+	Tier2 = Tier{PerMinute: 20, Burst: 20}
+	Tier3 = Tier{PerMinute: 50, Burst: 50}
+	Tier4 = Tier{PerMinute: 100, Burst: 100}
+	// TierPostMessage allows about one message per second and channel
+	TierPostMessage = Tier{PerMinute: 60, Burst: 1, PerChannel: true}
+)
+
+// methodTiers are the tiers of the methods the client calls, methods which
+// are not listed are limited by Tier3
+var methodTiers = map[string]Tier{
+	"apps.connections.open":        Tier1,
+	"auth.test":                    Tier4,
+	"chat.delete":                  Tier3,
+	"chat.deleteScheduledMessage":  Tier3,
+	"chat.getPermalink":            Tier4,
+	"chat.meMessage":               Tier3,
+	"chat.postEphemeral":           Tier4,
+	"chat.postMessage":             TierPostMessage,
+	"chat.scheduleMessage":         Tier3,
+	"chat.update":                  Tier3,
+	"conversations.history":        Tier3,
+	"conversations.list":           Tier2,
+	"conversations.members":        Tier4,
+	"conversations.replies":        Tier3,
+	"files.completeUploadExternal": Tier4,
+	"files.getUploadURLExternal":   Tier4,
+	"files.upload":                 Tier2,
+	"reactions.add":                Tier3,
+	"reactions.get":                Tier3,
+	"reactions.remove":             Tier2,
+	"rtm.start":                    Tier1,
+	"users.list":                   Tier2,
+	"views.open":                   Tier4,
+}
+
+func newRateLimiter() *rateLimiter {
+	tiers := make(map[string]Tier, len(methodTiers))
+	for method, tier := range methodTiers {
+		tiers[method] = tier
+	}
+
+	return &rateLimiter{tiers: tiers, buckets: map[string]*bucket{}}
+}
+
+const (
+	defaultMaxRetries = 3
+	defaultRetryAfter = time.Second
+)
+
+// Stats counts the requests of a client
+type Stats struct {
+	Requests int64
+	// Throttled is the number of requests which were delayed by the rate limiter.
+	Throttled int64
+	// RateLimited is the number of requests slack answered with HTTP 429.
+	RateLimited int64
+	// Waited is how long requests were delayed in total.
+	Waited time.Duration
+}
+
+// bucket is a token bucket of a method, or of a method and channel
+type bucket struct {
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+// rateLimiter spaces the calls of each method according to its tier
+type rateLimiter struct {
+	mu      sync.Mutex
+	tiers   map[string]Tier
+	buckets map[string]*bucket
+}
+
+func (l *rateLimiter) tier(method string) Tier {
+	if tier, ok := l.tiers[method]; ok {
+		return tier
+	}
+
+	return Tier3
+}
+
+// key returns the bucket key of the call
+func (l *rateLimiter) key(method, channel string) string {
+	if l.tier(method).PerChannel && channel != "" {
+		return method + ":" + channel
+	}
+
+	return method
+}
+
+// reserve takes a token of the bucket and returns how long to wait for it
+func (l *rateLimiter) reserve(method, key string, now time.Time) time.Duration {
+	tier := l.tier(method)
+	rate := float64(tier.PerMinute) / float64(time.Minute)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(tier.Burst), last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens += float64(now.Sub(b.last)) * rate
+	if b.tokens > float64(tier.Burst) {
+		b.tokens = float64(tier.Burst)
+	}
+	b.last = now
+	b.tokens--
+
+	var wait time.Duration
+	if b.tokens < 0 && rate > 0 {
+		wait = time.Duration(math.Ceil(-b.tokens / rate))
+	}
+
+	if blocked := b.blockedUntil.Sub(now); blocked > wait {
+		wait = blocked
+	}
+
+	return wait
+}
+
+// release gives back a token reserved for a call which was not made
+func (l *rateLimiter) release(method, key string) {
+	tier := l.tier(method)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok := l.buckets[key]; ok && b.tokens < float64(tier.Burst) {
+		b.tokens++
+	}
+}
+
+// block pauses the bucket until slack accepts calls again
+func (l *rateLimiter) block(key string, until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{last: time.Now()}
+		l.buckets[key] = b
+	}
+
+	if until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+}
+
+// wait blocks until the call may be made or the context is done
+func (c *Client) wait(ctx context.Context, method, key string) error {
+	if c.limiter == nil {
+		return nil
+	}
+
+	delay := c.limiter.reserve(method, key, time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	c.statsMu.Lock()
+	c.stats.Throttled++
+	c.stats.Waited += delay
+	c.statsMu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		c.limiter.release(method, key)
+		return ctx.Err()
+	}
+}
+
+// Stats returns the counters of the client's requests
+func (c *Client) Stats() Stats {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+
+	return c.stats
+}
+
+// retryAfter reads the Retry-After header of a rate limited response
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return defaultRetryAfter
+	}
+
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,131 @@
+package webapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	limiter := newRateLimiter()
+	now := time.Now()
+
+	// one message per second and channel, without burst
+	key := limiter.key("chat.postMessage", "C1")
+	if wait := limiter.reserve("chat.postMessage", key, now); wait != 0 {
+		t.Errorf("first message should not wait, waited %s", wait)
+	}
+	if wait := limiter.reserve("chat.postMessage", key, now); wait != time.Second {
+		t.Errorf("second message should wait a second, waited %s", wait)
+	}
+
+	other := limiter.key("chat.postMessage", "C2")
+	if wait := limiter.reserve("chat.postMessage", other, now); wait != 0 {
+		t.Errorf("message to another channel should not wait, waited %s", wait)
+	}
+
+	// tier 1 allows a burst of 2 calls, then one call every minute
+	for i := 0; i < 2; i++ {
+		if wait := limiter.reserve("rtm.start", "rtm.start", now); wait != 0 {
+			t.Fatalf("call %d of the burst waited %s", i, wait)
+		}
+	}
+	if wait := limiter.reserve("rtm.start", "rtm.start", now); wait != time.Minute {
+		t.Errorf("call after the burst should wait a minute, waited %s", wait)
+	}
+
+	// a released reservation is given back to the next call
+	limiter.release("rtm.start", "rtm.start")
+	if wait := limiter.reserve("rtm.start", "rtm.start", now); wait != time.Minute {
+		t.Errorf("call after the release should wait a minute, waited %s", wait)
+	}
+
+	limiter.block("auth.test", now.Add(time.Minute))
+	if wait := limiter.reserve("auth.test", "auth.test", now); wait != time.Minute {
+		t.Errorf("blocked method should wait a minute, waited %s", wait)
+	}
+}
+
+func TestWaitCancelled(t *testing.T) {
+	client := NewClient("123", WithMethodTier("auth.test", Tier{PerMinute: 1, Burst: 1}))
+
+	if err := client.wait(context.Background(), "auth.test", "auth.test"); err != nil {
+		t.Fatalf("first call should not wait %#v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.wait(ctx, "auth.test", "auth.test"); err != context.Canceled {
+		t.Fatalf("expected cancelled wait, got %#v", err)
+	}
+
+	// the cancelled call did not use up the next token
+	if wait := client.limiter.reserve("auth.test", "auth.test", time.Now()); wait > time.Minute {
+		t.Errorf("expected to wait at most a minute, waited %s", wait)
+	}
+}
+
+func TestWithoutRateLimit(t *testing.T) {
+	client := NewClient("123", WithoutRateLimit(), WithMethodTier("auth.test", Tier1))
+	if client.err != nil || client.limiter != nil {
+		t.Errorf("expected a client without rate limit, got %#v", client)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	calls := 0
+	httpmock.RegisterResponder("POST", "https://slack.com/api/auth.test", func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			response := httpmock.NewStringResponse(429, `{"ok": false, "error": "ratelimited"}`)
+			response.Header.Set("Retry-After", "0")
+			return response, nil
+		}
+
+		return httpmock.NewJsonResponse(200, &AuthTest{APIResponse: APIResponse{OK: true}, UserID: "U123"})
+	})
+
+	client := NewClient("123")
+	auth, err := client.AuthTest()
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if auth.UserID != "U123" || calls != 2 {
+		t.Errorf("expected retried call, got %d calls", calls)
+	}
+
+	stats := client.Stats()
+	if stats.Requests != 2 || stats.RateLimited != 1 {
+		t.Errorf("unexpected stats %#v", stats)
+	}
+}
+
+func TestRetryAfterExhausted(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "https://slack.com/api/auth.test", func(req *http.Request) (*http.Response, error) {
+		response := httpmock.NewStringResponse(429, "")
+		response.Header.Set("Retry-After", "0")
+		return response, nil
+	})
+
+	client := NewClient("123", WithMaxRetries(2))
+	_, err := client.AuthTest()
+
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.Code != "ratelimited" {
+		t.Fatalf("expected ratelimited error, got %#v", err)
+	}
+
+	if stats := client.Stats(); stats.Requests != 3 {
+		t.Errorf("expected 3 requests, got %#v", stats)
+	}
+}
@@ -0,0 +1,71 @@
+package webapi
+
+import (
+	"context"
+	"net/url"
+)
+
+// Reaction is an emoji reaction to a message
+type Reaction struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Users []string `json:"users"`
+}
+
+// ReactionsAdd adds the emoji reaction, given by its name without colons,
+// to the message
+func (c *Client) ReactionsAdd(channel, timeStamp, name string) error {
+	return c.ReactionsAddContext(context.Background(), channel, timeStamp, name)
+}
+
+// ReactionsAddContext is ReactionsAdd with a context
+func (c *Client) ReactionsAddContext(ctx context.Context, channel, timeStamp, name string) error {
+	return c.PostContext(ctx, "reactions.add", reactionValues(channel, timeStamp, name), &APIResponse{})
+}
+
+// ReactionsRemove removes the emoji reaction of the bot from the message
+func (c *Client) ReactionsRemove(channel, timeStamp, name string) error {
+	return c.ReactionsRemoveContext(context.Background(), channel, timeStamp, name)
+}
+
+// ReactionsRemoveContext is ReactionsRemove with a context
+func (c *Client) ReactionsRemoveContext(ctx context.Context, channel, timeStamp, name string) error {
+	return c.PostContext(ctx, "reactions.remove", reactionValues(channel, timeStamp, name), &APIResponse{})
+}
+
+// ReactionsGetResponse is returned by reactions.get
+type ReactionsGetResponse struct {
+	APIResponse
+	Type    string  `json:"type"`
+	Channel string  `json:"channel"`
+	Message Message `json:"message"`
+}
+
+// ReactionsGet returns the reactions to the message
+func (c *Client) ReactionsGet(channel, timeStamp string) ([]Reaction, error) {
+	return c.ReactionsGetContext(context.Background(), channel, timeStamp)
+}
+
+// ReactionsGetContext is ReactionsGet with a context
+func (c *Client) ReactionsGetContext(ctx context.Context, channel, timeStamp string) ([]Reaction, error) {
+	query := url.Values{}
+	query.Add("channel", channel)
+	query.Add("timestamp", timeStamp)
+	query.Add("full", "true")
+
+	response := &ReactionsGetResponse{}
+	if err := c.GetContext(ctx, "reactions.get", &query, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Message.Reactions, nil
+}
+
+func reactionValues(channel, timeStamp, name string) url.Values {
+	values := url.Values{}
+	values.Add("channel", channel)
+	values.Add("timestamp", timeStamp)
+	values.Add("name", name)
+
+	return values
+}
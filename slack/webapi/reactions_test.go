@@ -0,0 +1,63 @@
+package webapi
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestReactionsAddAndRemove(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var added, removed url.Values
+	httpmock.RegisterResponder("POST", "https://slack.com/api/reactions.add", formResponder(&added, &APIResponse{OK: true}))
+	httpmock.RegisterResponder("POST", "https://slack.com/api/reactions.remove", formResponder(&removed, &APIResponse{Error: "no_reaction"}))
+
+	client := NewClient("123")
+	if err := client.ReactionsAdd("C1", "1.2", "white_check_mark"); err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if added.Get("channel") != "C1" || added.Get("timestamp") != "1.2" || added.Get("name") != "white_check_mark" {
+		t.Errorf("unexpected request %v", added)
+	}
+
+	err := client.ReactionsRemove("C1", "1.2", "eyes")
+	if !errors.Is(err, ErrNoReaction) {
+		t.Errorf("unexpected error %#v", err)
+	}
+
+	if removed.Get("name") != "eyes" {
+		t.Errorf("unexpected request %v", removed)
+	}
+}
+
+func TestReactionsGet(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "https://slack.com/api/reactions.get", httpmock.NewStringResponder(200, `{
+		"ok": true,
+		"type": "message",
+		"channel": "C1",
+		"message": {
+			"type": "message",
+			"text": "deploy?",
+			"ts": "1.2",
+			"reactions": [{"name": "white_check_mark", "count": 2, "users": ["U1", "U2"]}]
+		}
+	}`))
+
+	client := NewClient("123")
+	reactions, err := client.ReactionsGet("C1", "1.2")
+	if err != nil {
+		t.Fatalf("something went wrong %#v", err)
+	}
+
+	if len(reactions) != 1 || reactions[0].Name != "white_check_mark" || reactions[0].Count != 2 || len(reactions[0].Users) != 2 {
+		t.Errorf("unexpected reactions %#v", reactions)
+	}
+}
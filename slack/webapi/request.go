@@ -31,17 +31,20 @@ type MessageAttachment struct {
 
 // PostMessage struct
 type PostMessage struct {
-	Channel     string
-	Text        string
-	Parse       string
-	LinkNames   int
-	Attachments []*MessageAttachment
-	UnfurlLinks bool
-	UnfurlMedia bool
-	UserName    string
-	AsUser      bool
-	IconURL     string
-	IconEmoji   string
+	Channel         string
+	Text            string
+	ThreadTimeStamp string
+	ReplyBroadcast  bool
+	Parse           string
+	LinkNames       int
+	Attachments     []*MessageAttachment
+	Blocks          []Block
+	UnfurlLinks     bool
+	UnfurlMedia     bool
+	UserName        string
+	AsUser          bool
+	IconURL         string
+	IconEmoji       string
 }
 
 // ToURLValues method
@@ -50,10 +53,14 @@ func (message *PostMessage) ToURLValues() url.Values {
 	values.Add("channel", message.Channel)
 	values.Add("text", message.Text)
 	values.Add("parse", message.Parse)
-	values.Add("link_names", string(message.LinkNames))
+	values.Add("link_names", strconv.Itoa(message.LinkNames))
 	values.Add("unfurl_links", strconv.FormatBool(message.UnfurlLinks))
 	values.Add("unfurl_media", strconv.FormatBool(message.UnfurlMedia))
 	values.Add("as_user", strconv.FormatBool(message.AsUser))
+	if message.ThreadTimeStamp != "" {
+		values.Add("thread_ts", message.ThreadTimeStamp)
+		values.Add("reply_broadcast", strconv.FormatBool(message.ReplyBroadcast))
+	}
 	if message.UserName != "" {
 		values.Add("user_name", message.UserName)
 	}
@@ -67,6 +74,10 @@ func (message *PostMessage) ToURLValues() url.Values {
 		s, _ := json.Marshal(message.Attachments)
 		values.Add("attachments", string(s))
 	}
+	if len(message.Blocks) > 0 {
+		s, _ := json.Marshal(message.Blocks)
+		values.Add("blocks", string(s))
+	}
 
 	return values
 }
@@ -82,3 +93,24 @@ func NewPostMessage(channel string, text string) *PostMessage {
 		UnfurlMedia: true,
 	}
 }
+
+// ResponseMessage is posted to the response_url of a slash command or an
+// interaction
+type ResponseMessage struct {
+	ResponseType    string  `json:"response_type,omitempty"`
+	Text            string  `json:"text"`
+	ReplaceOriginal bool    `json:"replace_original,omitempty"`
+	DeleteOriginal  bool    `json:"delete_original,omitempty"`
+	Blocks          []Block `json:"blocks,omitempty"`
+}
+
+// NewResponseMessage creates new ResponseMessage, which is only visible to
+// the user unless inChannel is set
+func NewResponseMessage(text string, inChannel bool) *ResponseMessage {
+	responseType := "ephemeral"
+	if inChannel {
+		responseType = "in_channel"
+	}
+
+	return &ResponseMessage{ResponseType: responseType, Text: text}
+}
@@ -5,7 +5,35 @@ type SlackTimeStamp int64
 
 // APIResponse provides common fields shared by all API response.
 type APIResponse struct {
-	OK bool `json:"ok"`
+	OK               bool              `json:"ok"`
+	Error            string            `json:"error,omitempty"`
+	Warning          string            `json:"warning,omitempty"`
+	ResponseMetadata *ResponseMetadata `json:"response_metadata,omitempty"`
+}
+
+// ResponseMetadata contains details of errors and warnings, and the cursor
+// of the next page of paginated responses
+type ResponseMetadata struct {
+	Messages   []string `json:"messages,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// NextCursor returns the cursor of the next page, it is empty on the last page
+func (r *APIResponse) NextCursor() string {
+	if r.ResponseMetadata == nil {
+		return ""
+	}
+
+	return r.ResponseMetadata.NextCursor
+}
+
+// PostMessageResponse is returned by chat.postMessage
+type PostMessageResponse struct {
+	APIResponse
+	Channel   string   `json:"channel"`
+	TimeStamp string   `json:"ts"`
+	Message   *Message `json:"message,omitempty"`
 }
 
 // Self property contains details on the authenticated user.
@@ -22,6 +50,7 @@ type UserProfile struct {
 	LastName           string `json:"last_name"`
 	RealName           string `json:"real_name"`
 	RealNameNormalized string `json:"real_name_normalized"`
+	DisplayName        string `json:"display_name"`
 	Email              string `json:"email"`
 	Skype              string `json:"skype"`
 	Phone              string `json:"phone"`
@@ -79,8 +108,15 @@ type Purpose struct {
 
 // Message provides information about your message.
 type Message struct {
-	User string `json:"user"`
-	Text string `json:"text"`
+	Type       string     `json:"type,omitempty"`
+	SubType    string     `json:"subtype,omitempty"`
+	User       string     `json:"user"`
+	BotID      string     `json:"bot_id,omitempty"`
+	Text       string     `json:"text"`
+	TimeStamp  string     `json:"ts,omitempty"`
+	ThreadTS   string     `json:"thread_ts,omitempty"`
+	ReplyCount int        `json:"reply_count,omitempty"`
+	Reactions  []Reaction `json:"reactions,omitempty"`
 }
 
 // Channel provides information about your channel.
@@ -166,3 +202,20 @@ type RtmStart struct {
 	Bots     []Bot     `json:"bots,omitempty"`
 	IMs      []IM      `json:"ims,omitempty"`
 }
+
+// AuthTest tells who the used token belongs to.
+type AuthTest struct {
+	APIResponse
+	URL    string `json:"url"`
+	Team   string `json:"team"`
+	User   string `json:"user"`
+	TeamID string `json:"team_id"`
+	UserID string `json:"user_id"`
+	BotID  string `json:"bot_id,omitempty"`
+}
+
+// AppsConnectionsOpen contains the Socket Mode WebSocket URL.
+type AppsConnectionsOpen struct {
+	APIResponse
+	URL string `json:"url"`
+}
@@ -0,0 +1,43 @@
+package webapi
+
+import (
+	"context"
+	"net/url"
+)
+
+// UsersListResponse is a page of users.list
+type UsersListResponse struct {
+	APIResponse
+	Members []User `json:"members"`
+}
+
+// UsersList returns every user of the workspace
+func (c *Client) UsersList() ([]User, error) {
+	return c.UsersListContext(context.Background())
+}
+
+// UsersListContext is UsersList with a context
+func (c *Client) UsersListContext(ctx context.Context) ([]User, error) {
+	var users []User
+	err := c.UsersListPages(ctx, 0, func(page []User) error {
+		users = append(users, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// UsersListPages passes the users of the workspace page by page to handle
+func (c *Client) UsersListPages(ctx context.Context, limit int, handle func([]User) error) error {
+	values := url.Values{}
+	values.Add("limit", pageSize(limit))
+
+	return c.Paginate(ctx, "users.list", values, func() Page {
+		return &UsersListResponse{}
+	}, func(page Page) error {
+		return handle(page.(*UsersListResponse).Members)
+	})
+}
@@ -0,0 +1,81 @@
+package webapi
+
+// View is a modal or an app home tab
+// ex. https://api.slack.com/reference/surfaces/views
+type View struct {
+	ID              string      `json:"id,omitempty"`
+	Type            string      `json:"type"`
+	Title           *TextObject `json:"title,omitempty"`
+	Submit          *TextObject `json:"submit,omitempty"`
+	Close           *TextObject `json:"close,omitempty"`
+	Blocks          Blocks      `json:"blocks"`
+	CallbackID      string      `json:"callback_id,omitempty"`
+	PrivateMetadata string      `json:"private_metadata,omitempty"`
+	ExternalID      string      `json:"external_id,omitempty"`
+	ClearOnClose    bool        `json:"clear_on_close,omitempty"`
+	NotifyOnClose   bool        `json:"notify_on_close,omitempty"`
+	Hash            string      `json:"hash,omitempty"`
+	State           *ViewState  `json:"state,omitempty"`
+}
+
+// NewModal creates new modal View
+func NewModal(callbackID, title string, blocks ...Block) *View {
+	return &View{
+		Type:       "modal",
+		Title:      NewPlainText(title),
+		Blocks:     blocks,
+		CallbackID: callbackID,
+	}
+}
+
+// Validate validates the view and its blocks against slack's limits
+func (v *View) Validate() error {
+	if err := validateText("title", v.Title, true, 24); err != nil {
+		return err
+	}
+
+	if v.Submit != nil {
+		if err := validateText("submit", v.Submit, true, 24); err != nil {
+			return err
+		}
+	}
+
+	if v.Close != nil {
+		if err := validateText("close", v.Close, true, 24); err != nil {
+			return err
+		}
+	}
+
+	if err := validateLength("callback_id", v.CallbackID, 255); err != nil {
+		return err
+	}
+
+	if err := validateLength("private_metadata", v.PrivateMetadata, 3000); err != nil {
+		return err
+	}
+
+	return ValidateBlocks(v.Blocks, MaxViewBlocks)
+}
+
+// ViewState contains the values of the input blocks of a submitted view,
+// by block_id and action_id
+type ViewState struct {
+	Values map[string]map[string]ActionState `json:"values"`
+}
+
+// ActionState is the value of an interactive element
+type ActionState struct {
+	Type            string         `json:"type"`
+	Value           string         `json:"value,omitempty"`
+	SelectedOption  *OptionObject  `json:"selected_option,omitempty"`
+	SelectedOptions []OptionObject `json:"selected_options,omitempty"`
+	SelectedDate    string         `json:"selected_date,omitempty"`
+	SelectedUser    string         `json:"selected_user,omitempty"`
+	SelectedChannel string         `json:"selected_channel,omitempty"`
+}
+
+// ViewResponse is returned by views.open
+type ViewResponse struct {
+	APIResponse
+	View *View `json:"view"`
+}